@@ -1,7 +1,23 @@
 package hal
 
 import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
 	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/textproto"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
 )
 
 //
@@ -21,11 +37,7 @@ func NewAttachment() *Attachment {
 }
 
 func (res *Attachment) Description() *Formattable {
-	f, err := DecodeFormattable(res.GetField("description"))
-	if err != nil {
-		return nil
-	}
-	return f
+	return res.GetFormattable("description")
 }
 
 func (res *Attachment) Id() int {
@@ -44,9 +56,488 @@ func (res *Attachment) ContentType() string {
 	return res.GetString("contentType")
 }
 
-func (res *Attachment) Download(c *HalClient) (io.Reader, error) {
+// Upload status for direct uploads: "prepared" or "uploaded".
+func (res *Attachment) Status() string {
+	return res.GetString("status")
+}
+
+// Digest of the file contents, e.g. ("md5", "<hex hash>").
+func (res *Attachment) Digest() (algorithm string, hash string) {
+	if m, ok := res.GetField("digest").(map[string]interface{}); ok {
+		algorithm, _ = m["algorithm"].(string)
+		hash, _ = m["hash"].(string)
+	}
+	return algorithm, hash
+}
+
+// Download the attachment.  When the attachment has an md5 `digest` it is
+// verified while streaming and the final `Read` returns `ErrDigestMismatch`
+// if the contents don't match.  The caller must close the returned reader.
+func (res *Attachment) Download(c *HalClient) (*FileReader, error) {
+	link := res.GetLink("downloadLocation")
+	f, err := c.LinkGetFile(link)
+	if err != nil {
+		return nil, err
+	}
+	if algorithm, hash := res.Digest(); strings.EqualFold(algorithm, "md5") && hash != "" {
+		f.ReadCloser = &digestReader{
+			ReadCloser: f.ReadCloser,
+			hash:       md5.New(),
+			expected:   hash,
+		}
+	}
+	return f, nil
+}
+
+// Resume a download at `offset`.  The digest can't be verified on partial
+// downloads.
+func (res *Attachment) DownloadFrom(c *HalClient, offset int64) (*FileReader, error) {
 	link := res.GetLink("downloadLocation")
-	return c.LinkGetFile(link)
+	if link == nil {
+		return nil, errors.New("nil Link")
+	}
+	return c.GetFileRange(link.Href, offset)
+}
+
+var ErrDigestMismatch = errors.New("Attachment digest mismatch")
+
+type digestReader struct {
+	io.ReadCloser
+
+	hash     hash.Hash
+	expected string
+}
+
+func (r *digestReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.hash.Write(p[:n])
+	if err == io.EOF {
+		if sum := hex.EncodeToString(r.hash.Sum(nil)); !strings.EqualFold(sum, r.expected) {
+			return n, ErrDigestMismatch
+		}
+	}
+	return n, err
+}
+
+func (res *ResourceObject) getAttachments(c *HalClient) *Collection {
+	// Get embedded attachments or load from a link
+	val := res.GetEmbeddedResource("attachments", c)
+	if col, ok := val.(*Collection); ok {
+		return col
+	}
+	return nil
+}
+
+//
+// Attachment upload
+//
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func writeAttachmentParts(mw *multipart.Writer, metadata []byte, fileName, contentType string, r io.Reader) error {
+	// metadata part
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition", `form-data; name="metadata"`)
+	h.Set("Content-Type", "application/json")
+	part, err := mw.CreatePart(h)
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write(metadata); err != nil {
+		return err
+	}
+	// file part
+	h = make(textproto.MIMEHeader)
+	h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename="%s"`,
+		quoteEscaper.Replace(fileName)))
+	h.Set("Content-Type", contentType)
+	part, err = mw.CreatePart(h)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(part, r); err != nil {
+		return err
+	}
+	return mw.Close()
+}
+
+// Upload a file to an `addAttachment` link.  The multipart body is streamed
+// from `r`, so the file is never buffered in memory.
+func (c *HalClient) UploadAttachment(link *Link, fileName, contentType, description string, r io.Reader) (*Attachment, error) {
+	if link == nil {
+		return nil, errors.New("nil Link")
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	meta := map[string]interface{}{
+		"fileName": fileName,
+	}
+	if description != "" {
+		meta["description"] = map[string]string{
+			"raw": description,
+		}
+	}
+	metadata, err := json.Marshal(meta)
+	if err != nil {
+		return nil, err
+	}
+
+	pr, pw := io.Pipe()
+	defer pr.Close()
+	mw := multipart.NewWriter(pw)
+	go func() {
+		pw.CloseWithError(writeAttachmentParts(mw, metadata, fileName, contentType, r))
+	}()
+
+	res, err := c.PostContent(link.Href, mw.FormDataContentType(), pr)
+	if err != nil {
+		return nil, err
+	}
+	// Make sure it is an Attachment
+	if att, ok := res.(*Attachment); ok {
+		return att, nil
+	}
+	return nil, fmt.Errorf("Unknown resource type: %s", res.ResourceType())
+}
+
+//
+// Direct upload
+//
+// Instances backed by object storage (fog/S3) don't accept file uploads
+// directly.  Instead the attachment is "prepared", the file is posted to the
+// presigned storage form and then the upload is marked as completed.
+//
+
+// Find the number of bytes left in `r`.  Readers of unknown size are
+// buffered into memory.
+func readerSize(r io.Reader) (int64, io.Reader, error) {
+	switch v := r.(type) {
+	case interface{ Len() int }:
+		return int64(v.Len()), r, nil
+	case io.Seeker:
+		cur, err := v.Seek(0, io.SeekCurrent)
+		if err != nil {
+			break
+		}
+		end, err := v.Seek(0, io.SeekEnd)
+		if err != nil {
+			break
+		}
+		if _, err := v.Seek(cur, io.SeekStart); err != nil {
+			return 0, nil, err
+		}
+		return end - cur, r, nil
+	}
+	buf := &bytes.Buffer{}
+	n, err := io.Copy(buf, r)
+	if err != nil {
+		return 0, nil, err
+	}
+	return n, buf, nil
+}
+
+// Post the file to a presigned storage form.  Storage services don't accept
+// chunked uploads, so the multipart body is sent with a known length.
+func (c *HalClient) uploadToStorage(link *Link, fileName, contentType string, size int64, r io.Reader) error {
+	buf := &bytes.Buffer{}
+	mw := multipart.NewWriter(buf)
+	// Form fields must come before the file.
+	keys := make([]string, 0, len(link.FormFields))
+	for k := range link.FormFields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if err := mw.WriteField(k, link.FormFields[k]); err != nil {
+			return err
+		}
+	}
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename="%s"`,
+		quoteEscaper.Replace(fileName)))
+	h.Set("Content-Type", contentType)
+	if _, err := mw.CreatePart(h); err != nil {
+		return err
+	}
+	prefix := append([]byte(nil), buf.Bytes()...)
+	buf.Reset()
+	if err := mw.Close(); err != nil {
+		return err
+	}
+	suffix := buf.Bytes()
+
+	body := io.MultiReader(bytes.NewReader(prefix), io.LimitReader(r, size), bytes.NewReader(suffix))
+	req, err := c.newRequest("POST", link.Href, body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = int64(len(prefix)) + size + int64(len(suffix))
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("Direct upload failed: %s: %s", resp.Status, string(msg))
+	}
+	return nil
+}
+
+// Upload a file using a `prepareAttachment` link.
+func (c *HalClient) DirectUploadAttachment(link *Link, fileName, contentType, description string, r io.Reader) (*Attachment, error) {
+	if link == nil {
+		return nil, errors.New("nil Link")
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	size, r, err := readerSize(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Prepare attachment
+	meta := NewAttachment()
+	meta.SetField("fileName", fileName)
+	meta.SetField("fileSize", size)
+	meta.SetField("contentType", contentType)
+	if description != "" {
+		meta.SetField("description", map[string]string{
+			"raw": description,
+		})
+	}
+	res, err := c.Post(link.Href, meta)
+	if err != nil {
+		return nil, err
+	}
+	prepared, ok := res.(*Attachment)
+	if !ok {
+		return nil, fmt.Errorf("Unknown resource type: %s", res.ResourceType())
+	}
+	storage := prepared.GetLink("addAttachment")
+	if storage == nil {
+		return nil, errors.New("No 'addAttachment' Link")
+	}
+	complete := prepared.GetLink("completeUpload")
+	if complete == nil {
+		return nil, errors.New("No 'completeUpload' Link")
+	}
+
+	// Upload file to storage
+	if err := c.uploadToStorage(storage, fileName, contentType, size, r); err != nil {
+		return nil, err
+	}
+
+	// Complete upload
+	res, err = c.LinkGet(complete)
+	if err != nil {
+		return nil, err
+	}
+	if att, ok := res.(*Attachment); ok {
+		return att, nil
+	}
+	return nil, fmt.Errorf("Unknown resource type: %s", res.ResourceType())
+}
+
+// Check if files can be uploaded directly to the storage backend of a
+// container (WorkPackage, WikiPage, ...).  The server only adds the
+// `prepareAttachment` link when direct uploads are enabled.
+func SupportsDirectUploads(container Resource) bool {
+	return container.GetLink("prepareAttachment") != nil
+}
+
+// Attach a file to a resource (WorkPackage, WikiPage, ...) that has an
+// `addAttachment` or `prepareAttachment` link.
+func (c *HalClient) AddAttachment(container Resource, fileName, contentType, description string, r io.Reader) (*Attachment, error) {
+	if link := container.GetLink("addAttachment"); link != nil {
+		return c.UploadAttachment(link, fileName, contentType, description, r)
+	}
+	if SupportsDirectUploads(container) {
+		link := container.GetLink("prepareAttachment")
+		return c.DirectUploadAttachment(link, fileName, contentType, description, r)
+	}
+	return nil, errors.New("No 'addAttachment' or 'prepareAttachment' Link")
+}
+
+//
+// Bulk download
+//
+
+type DownloadOptions struct {
+	// Maximum number of concurrent downloads, defaults to 4.
+	Concurrency int
+	// Download files even when an identical file already exists.
+	Overwrite bool
+}
+
+type DownloadStatus string
+
+const (
+	DownloadOK      DownloadStatus = "downloaded"
+	DownloadSkipped DownloadStatus = "skipped"
+	DownloadFailed  DownloadStatus = "failed"
+)
+
+type DownloadResult struct {
+	Attachment *Attachment
+	Path       string
+	Status     DownloadStatus
+	Size       int64
+	Err        error
+}
+
+// Make a remote file name safe to use as a local file name.
+func sanitizeFileName(name string) string {
+	name = path.Base(strings.Replace(name, "\\", "/", -1))
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+	name = strings.Trim(name, " .")
+	if name == "" {
+		return "attachment"
+	}
+	return name
+}
+
+// Check if `file` already has the contents of the attachment.
+func attachmentFileMatches(att *Attachment, file string) bool {
+	fi, err := os.Stat(file)
+	if err != nil || !fi.Mode().IsRegular() || fi.Size() != int64(att.FileSize()) {
+		return false
+	}
+	algorithm, hash := att.Digest()
+	if !strings.EqualFold(algorithm, "md5") || hash == "" {
+		// Size is all we can compare.
+		return true
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	h := md5.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return false
+	}
+	return strings.EqualFold(hex.EncodeToString(h.Sum(nil)), hash)
+}
+
+// Download to a temporary file and rename it, so partial downloads never
+// look like complete files.
+func downloadAttachmentFile(c *HalClient, att *Attachment, file string) (int64, error) {
+	f, err := att.Download(c)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	tmp, err := ioutil.TempFile(filepath.Dir(file), ".download-")
+	if err != nil {
+		return 0, err
+	}
+	n, err := io.Copy(tmp, f)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil && att.HasField("fileSize") && n != int64(att.FileSize()) {
+		err = fmt.Errorf("Downloaded %d bytes, expected %d", n, att.FileSize())
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), file)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return n, err
+	}
+	return n, nil
+}
+
+// Download attachments into `dir`.  Attachments with the same file name get
+// their id appended to the name.  The report is in the same order as
+// `attachments`.
+func DownloadAttachments(c *HalClient, attachments []*Attachment, dir string, opts *DownloadOptions) ([]DownloadResult, error) {
+	if opts == nil {
+		opts = &DownloadOptions{}
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	// Pick unique file names, lowest id first so names are stable between runs.
+	order := make([]int, len(attachments))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return attachments[order[a]].Id() < attachments[order[b]].Id()
+	})
+	results := make([]DownloadResult, len(attachments))
+	used := make(map[string]bool)
+	for _, i := range order {
+		att := attachments[i]
+		name := sanitizeFileName(att.FileName())
+		if used[strings.ToLower(name)] {
+			ext := filepath.Ext(name)
+			base := strings.TrimSuffix(name, ext)
+			name = fmt.Sprintf("%s (%d)%s", base, att.Id(), ext)
+			for n := 2; used[strings.ToLower(name)]; n++ {
+				name = fmt.Sprintf("%s (%d-%d)%s", base, att.Id(), n, ext)
+			}
+		}
+		used[strings.ToLower(name)] = true
+		results[i] = DownloadResult{
+			Attachment: att,
+			Path:       filepath.Join(dir, name),
+		}
+	}
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i := range results {
+		r := &results[i]
+		if !opts.Overwrite && attachmentFileMatches(r.Attachment, r.Path) {
+			r.Status = DownloadSkipped
+			r.Size = int64(r.Attachment.FileSize())
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			r.Size, r.Err = downloadAttachmentFile(c, r.Attachment, r.Path)
+			if r.Err != nil {
+				r.Status = DownloadFailed
+			} else {
+				r.Status = DownloadOK
+			}
+		}()
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, r := range results {
+		if r.Err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return results, fmt.Errorf("%d of %d attachments failed to download", failed, len(results))
+	}
+	return results, nil
 }
 
 // Register Factories
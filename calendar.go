@@ -0,0 +1,284 @@
+package hal
+
+import (
+	"fmt"
+	"time"
+)
+
+//
+// WeekDay
+//
+
+type WeekDay struct {
+	ResourceObject
+}
+
+func NewWeekDay() *WeekDay {
+	return &WeekDay{
+		ResourceObject{
+			Type: "WeekDay",
+		},
+	}
+}
+
+// ISO day of the week: 1 = Monday ... 7 = Sunday.
+func (res *WeekDay) Day() int {
+	return res.GetInt("day")
+}
+
+func (res *WeekDay) Name() string {
+	return res.GetString("name")
+}
+
+func (res *WeekDay) Working() bool {
+	return res.GetBool("working")
+}
+
+//
+// NonWorkingDay
+//
+
+type NonWorkingDay struct {
+	ResourceObject
+}
+
+func NewNonWorkingDay() *NonWorkingDay {
+	return &NonWorkingDay{
+		ResourceObject{
+			Type: "NonWorkingDay",
+		},
+	}
+}
+
+func (res *NonWorkingDay) Date() *time.Time {
+	if dt, err := res.GetDate("date"); err == nil {
+		return &dt
+	}
+	return nil
+}
+
+func (res *NonWorkingDay) Name() string {
+	return res.GetString("name")
+}
+
+//
+// Day
+//
+
+type Day struct {
+	ResourceObject
+}
+
+func NewDay() *Day {
+	return &Day{
+		ResourceObject{
+			Type: "Day",
+		},
+	}
+}
+
+func (res *Day) Date() *time.Time {
+	if dt, err := res.GetDate("date"); err == nil {
+		return &dt
+	}
+	return nil
+}
+
+func (res *Day) Name() string {
+	return res.GetString("name")
+}
+
+func (res *Day) Working() bool {
+	return res.GetBool("working")
+}
+
+//
+// Days API
+//
+
+func (c *HalClient) getDays(path string, filters *Filters) ([]Resource, error) {
+	col, err := c.GetFilteredCollection(path, filters)
+	if err != nil {
+		return nil, err
+	}
+	return col.AllItems(c)
+}
+
+func (c *HalClient) GetWeekDays() ([]*WeekDay, error) {
+	items, err := c.getDays(c.apiRootPath()+"/days/week", nil)
+	if err != nil {
+		return nil, err
+	}
+	days := make([]*WeekDay, 0, len(items))
+	for _, item := range items {
+		day, ok := item.(*WeekDay)
+		if !ok {
+			return nil, fmt.Errorf("Unknown resource type: %s", item.ResourceType())
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+// Get the non-working days (holidays) between `from` and `to`.
+func (c *HalClient) GetNonWorkingDays(from time.Time, to time.Time) ([]*NonWorkingDay, error) {
+	filters := NewFilters().DateRange("date", from, to)
+	items, err := c.getDays(c.apiRootPath()+"/days/non_working", filters)
+	if err != nil {
+		return nil, err
+	}
+	days := make([]*NonWorkingDay, 0, len(items))
+	for _, item := range items {
+		day, ok := item.(*NonWorkingDay)
+		if !ok {
+			return nil, fmt.Errorf("Unknown resource type: %s", item.ResourceType())
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+func (c *HalClient) GetDays(from time.Time, to time.Time) ([]*Day, error) {
+	filters := NewFilters().DateRange("date", from, to)
+	items, err := c.getDays(c.apiRootPath()+"/days", filters)
+	if err != nil {
+		return nil, err
+	}
+	days := make([]*Day, 0, len(items))
+	for _, item := range items {
+		day, ok := item.(*Day)
+		if !ok {
+			return nil, fmt.Errorf("Unknown resource type: %s", item.ResourceType())
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+// Load the server's working days and the non-working days between `from`
+// and `to`.
+func (c *HalClient) GetWorkCalendar(from time.Time, to time.Time) (*WorkCalendar, error) {
+	weekDays, err := c.GetWeekDays()
+	if err != nil {
+		return nil, err
+	}
+	nonWorkingDays, err := c.GetNonWorkingDays(from, to)
+	if err != nil {
+		return nil, err
+	}
+	return NewWorkCalendar(weekDays, nonWorkingDays), nil
+}
+
+//
+// WorkCalendar
+//
+// Local working day calculations, matching the server's `duration` field:
+// a duration counts the working days from the start date to the due date,
+// both included.
+//
+
+type WorkCalendar struct {
+	// Indexed by `time.Weekday`
+	workingWeekDays [7]bool
+	nonWorkingDays  map[string]bool
+}
+
+// Create a calendar from the server's week days and non-working days.  Week
+// days that aren't listed default to Monday - Friday being working days.
+func NewWorkCalendar(weekDays []*WeekDay, nonWorkingDays []*NonWorkingDay) *WorkCalendar {
+	cal := &WorkCalendar{
+		nonWorkingDays: make(map[string]bool),
+	}
+	for wd := time.Monday; wd <= time.Friday; wd++ {
+		cal.workingWeekDays[wd] = true
+	}
+	for _, day := range weekDays {
+		if d := day.Day(); d >= 1 && d <= 7 {
+			// ISO 7 (Sunday) is `time.Sunday` (0)
+			cal.workingWeekDays[d%7] = day.Working()
+		}
+	}
+	for _, day := range nonWorkingDays {
+		if date := day.Date(); date != nil {
+			cal.nonWorkingDays[date.Format("2006-01-02")] = true
+		}
+	}
+	return cal
+}
+
+func (cal *WorkCalendar) hasWorkingWeekDays() bool {
+	for _, working := range cal.workingWeekDays {
+		if working {
+			return true
+		}
+	}
+	return false
+}
+
+func truncateDate(date time.Time) time.Time {
+	y, m, d := date.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, date.Location())
+}
+
+func (cal *WorkCalendar) IsWorkingDay(date time.Time) bool {
+	return cal.workingWeekDays[date.Weekday()] &&
+		!cal.nonWorkingDays[date.Format("2006-01-02")]
+}
+
+// Move `n` working days after `date` (before it if `n` is negative).
+func (cal *WorkCalendar) AddWorkingDays(date time.Time, n int) time.Time {
+	date = truncateDate(date)
+	if !cal.hasWorkingWeekDays() {
+		return date
+	}
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+	for n > 0 {
+		date = date.AddDate(0, 0, step)
+		if cal.IsWorkingDay(date) {
+			n--
+		}
+	}
+	return date
+}
+
+// Count the working days from `from` to `to`, both included.  Returns 0 if
+// `to` is before `from`.
+func (cal *WorkCalendar) WorkingDaysBetween(from time.Time, to time.Time) int {
+	from, to = truncateDate(from), truncateDate(to)
+	count := 0
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		if cal.IsWorkingDay(d) {
+			count++
+		}
+	}
+	return count
+}
+
+// Get the due date of a work package starting on `start` with a `duration`
+// in working days.
+func (cal *WorkCalendar) DueDate(start time.Time, duration int) time.Time {
+	start = truncateDate(start)
+	if duration < 1 || !cal.hasWorkingWeekDays() {
+		return start
+	}
+	if cal.IsWorkingDay(start) {
+		duration--
+	}
+	return cal.AddWorkingDays(start, duration)
+}
+
+// Register Factories
+func init() {
+	resourceTypes["WeekDay"] = func() Resource {
+		return NewWeekDay()
+	}
+	resourceTypes["NonWorkingDay"] = func() Resource {
+		return NewNonWorkingDay()
+	}
+	resourceTypes["Day"] = func() Resource {
+		return NewDay()
+	}
+}
@@ -0,0 +1,153 @@
+package hal
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+//
+// Capability
+//
+// A permission (`action`) a principal has in a context (a project or global).
+//
+
+type Capability struct {
+	ResourceObject
+}
+
+func NewCapability() *Capability {
+	return &Capability{
+		ResourceObject{
+			Type: "Capability",
+		},
+	}
+}
+
+func (res *Capability) Id() string {
+	return res.GetString("id")
+}
+
+func (res *Capability) Action() string {
+	if l := res.GetLink("action"); l != nil {
+		return l.Title
+	}
+	return ""
+}
+
+func (c *HalClient) GetCapabilities(filters *Filters) (*Collection, error) {
+	return c.GetFilteredCollection(c.endpoint("/capabilities", "capabilities"), filters)
+}
+
+// Check if the current user is allowed to do `action` (e.g.
+// "memberships/create") in `context`: "g" for global actions or "p<id>"
+// for a project.
+func (c *HalClient) HasCapability(action string, context string) (bool, error) {
+	me, err := c.Me()
+	if err != nil {
+		return false, err
+	}
+	filters := NewFilters().
+		Filter("action", "=", action).
+		Filter("principal", "=", strconv.Itoa(me.Id())).
+		Filter("context", "=", context)
+	col, err := c.GetCapabilities(filters)
+	if err != nil {
+		return false, err
+	}
+	return col.Total() > 0, nil
+}
+
+//
+// Feature detection
+//
+
+const (
+	FeatureNotifications = "notifications"
+	FeatureFileLinks     = "fileLinks"
+	FeatureCapabilities  = "capabilities"
+	FeatureDays          = "days"
+	FeatureGrids         = "grids"
+)
+
+type feature struct {
+	// Root links that show the feature is available.
+	rels []string
+	// Endpoint to probe (below the API root) when the Root doesn't link it.
+	probe string
+}
+
+var features = map[string]feature{
+	FeatureNotifications: {rels: []string{"notifications"}, probe: "/notifications"},
+	FeatureFileLinks:     {rels: []string{"storages"}, probe: "/storages"},
+	FeatureCapabilities:  {rels: []string{"capabilities"}, probe: "/capabilities"},
+	FeatureDays:          {rels: []string{"days"}, probe: "/days/week"},
+	FeatureGrids:         {rels: []string{"grids"}, probe: "/grids"},
+}
+
+// Check if an endpoint exists.  Forbidden endpoints exist, they just aren't
+// usable by the current user.
+func (c *HalClient) probeEndpoint(path string) (bool, error) {
+	req, err := c.newRequestJSON("GET", path+"?pageSize=0", nil)
+	if err != nil {
+		return false, err
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return false, nil
+	case resp.StatusCode < 300, resp.StatusCode == http.StatusUnauthorized,
+		resp.StatusCode == http.StatusForbidden:
+		return true, nil
+	}
+	return false, responseError(resp)
+}
+
+// Check if the server supports a feature (e.g. `FeatureNotifications`).
+// Unknown feature names are looked up as Root links.  Results are cached.
+// Direct uploads depend on the container, see `SupportsDirectUploads`.
+func (c *HalClient) Supports(name string) (bool, error) {
+	c.featureLock.Lock()
+	defer c.featureLock.Unlock()
+	if supported, ok := c.features[name]; ok {
+		return supported, nil
+	}
+
+	f, ok := features[name]
+	if !ok {
+		f = feature{rels: []string{name}}
+	}
+	root, err := c.Root()
+	if err != nil {
+		return false, err
+	}
+	supported := false
+	for _, rel := range f.rels {
+		if root.GetLink(rel) != nil {
+			supported = true
+			break
+		}
+	}
+	if !supported && f.probe != "" {
+		if supported, err = c.probeEndpoint(c.apiRootPath() + f.probe); err != nil {
+			return false, fmt.Errorf("Failed to detect feature '%s': %v", name, err)
+		}
+	}
+
+	if c.features == nil {
+		c.features = make(map[string]bool)
+	}
+	c.features[name] = supported
+	return supported, nil
+}
+
+// Register Factories
+func init() {
+	resourceTypes["Capability"] = func() Resource {
+		return NewCapability()
+	}
+}
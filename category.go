@@ -0,0 +1,51 @@
+package hal
+
+//
+// Category
+//
+
+type Category struct {
+	ResourceObject
+}
+
+func NewCategory() *Category {
+	return &Category{
+		ResourceObject{
+			Type: "Category",
+		},
+	}
+}
+
+func (res *Category) Id() int {
+	return res.GetInt("id")
+}
+
+func (res *Category) Name() string {
+	return res.GetString("name")
+}
+
+func (res *Category) GetProject(c *HalClient) *Project {
+	// Get embedded project or load from a link
+	val := res.GetEmbeddedResource("project", c)
+	if p, ok := val.(*Project); ok {
+		return p
+	}
+	return nil
+}
+
+// Work packages in this category are assigned to the default assignee.
+func (res *Category) GetDefaultAssignee(c *HalClient) *User {
+	// Get embedded default assignee or load from a link
+	val := res.GetEmbeddedResource("defaultAssignee", c)
+	if u, ok := val.(*User); ok {
+		return u
+	}
+	return nil
+}
+
+// Register Factories
+func init() {
+	resourceTypes["Category"] = func() Resource {
+		return NewCategory()
+	}
+}
@@ -68,6 +68,21 @@ func (res *Collection) Items() []Resource {
 	return res.GetEmbeddedResourceList("elements")
 }
 
+// Load the items from this page and all following pages.
+func (res *Collection) AllItems(c *HalClient) ([]Resource, error) {
+	items := append([]Resource(nil), res.Items()...)
+	col := res
+	for col.GetLink("nextByOffset") != nil {
+		next, err := col.NextPage(c)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, next.Items()...)
+		col = next
+	}
+	return items, nil
+}
+
 // Register Resource Factories
 func init() {
 	resourceTypes["Collection"] = func() Resource {
@@ -0,0 +1,94 @@
+package hal
+
+import (
+	"fmt"
+)
+
+//
+// Configuration
+//
+
+type Configuration struct {
+	ResourceObject
+}
+
+func NewConfiguration() *Configuration {
+	return &Configuration{
+		ResourceObject{
+			Type: "Configuration",
+		},
+	}
+}
+
+// Maximum attachment size in bytes.
+func (res *Configuration) MaximumAttachmentFileSize() int {
+	return res.GetInt("maximumAttachmentFileSize")
+}
+
+func (res *Configuration) PerPageOptions() []int {
+	arr, _ := res.GetField("perPageOptions").([]interface{})
+	opts := make([]int, 0, len(arr))
+	for _, val := range arr {
+		if n, ok := val.(float64); ok {
+			opts = append(opts, int(n))
+		}
+	}
+	return opts
+}
+
+func (res *Configuration) HostName() string {
+	return res.GetString("hostName")
+}
+
+// Date format in Ruby `strftime` syntax, "" if the user's locale is used.
+func (res *Configuration) DateFormat() string {
+	return res.GetString("dateFormat")
+}
+
+// Time format in Ruby `strftime` syntax, "" if the user's locale is used.
+func (res *Configuration) TimeFormat() string {
+	return res.GetString("timeFormat")
+}
+
+// First day of the week (1 = Monday, 7 = Sunday), 0 if the user's locale is used.
+func (res *Configuration) StartOfWeek() int {
+	return res.GetInt("startOfWeek")
+}
+
+func (res *Configuration) ActiveFeatureFlags() []string {
+	arr, _ := res.GetField("activeFeatureFlags").([]interface{})
+	flags := make([]string, 0, len(arr))
+	for _, val := range arr {
+		if s, ok := val.(string); ok {
+			flags = append(flags, s)
+		}
+	}
+	return flags
+}
+
+func (res *Configuration) GetUserPreferences(c *HalClient) *UserPreferences {
+	// Get embedded preferences or load from a link
+	val := res.GetEmbeddedResource("userPreferences", c)
+	if p, ok := val.(*UserPreferences); ok {
+		return p
+	}
+	return nil
+}
+
+func (c *HalClient) Configuration() (*Configuration, error) {
+	res, err := c.Get(c.endpoint("/configuration", "configuration"))
+	if err != nil {
+		return nil, err
+	}
+	if conf, ok := res.(*Configuration); ok {
+		return conf, nil
+	}
+	return nil, fmt.Errorf("Unknown resource type: %s", res.ResourceType())
+}
+
+// Register Factories
+func init() {
+	resourceTypes["Configuration"] = func() Resource {
+		return NewConfiguration()
+	}
+}
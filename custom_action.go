@@ -0,0 +1,107 @@
+package hal
+
+import (
+	"errors"
+	"fmt"
+)
+
+//
+// CustomAction
+//
+// Predefined changes (status, assignee, ...) applied to a work package in
+// one step.
+//
+
+type CustomAction struct {
+	ResourceObject
+}
+
+func NewCustomAction() *CustomAction {
+	return &CustomAction{
+		ResourceObject{
+			Type: "CustomAction",
+		},
+	}
+}
+
+func (res *CustomAction) Name() string {
+	return res.GetString("name")
+}
+
+func (res *CustomAction) Description() string {
+	return res.GetString("description")
+}
+
+func (res *WorkPackage) GetCustomActions(c *HalClient) ([]*CustomAction, error) {
+	// Get embedded custom actions or load each from a link
+	var items []Resource
+	if embedded := res.GetEmbeddedResourceList("customActions"); embedded != nil {
+		items = embedded
+	} else {
+		for _, link := range res.GetLinks("customActions") {
+			link := link
+			linkRes, err := c.LinkGet(&link)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, linkRes)
+		}
+	}
+	actions := make([]*CustomAction, 0, len(items))
+	for _, item := range items {
+		action, ok := item.(*CustomAction)
+		if !ok {
+			return nil, fmt.Errorf("Unknown resource type: %s", item.ResourceType())
+		}
+		actions = append(actions, action)
+	}
+	return actions, nil
+}
+
+func (res *WorkPackage) executeCustomAction(c *HalClient, link *Link) (*WorkPackage, error) {
+	payload := &ResourceObject{}
+	payload.SetField("lockVersion", res.LockVersion())
+	if l := res.GetLink("self"); l != nil {
+		payload.AddLink("workPackage", *l)
+	}
+	newRes, err := c.Post(link.Href, payload)
+	if err != nil {
+		return nil, err
+	}
+	if wp, ok := newRes.(*WorkPackage); ok {
+		return wp, nil
+	}
+	return nil, fmt.Errorf("Unknown resource type: %s", newRes.ResourceType())
+}
+
+// Execute a custom action on this work package.  If the work package was
+// changed since it was loaded, the `ErrorUpdateConflict` error is returned
+// together with the reloaded work package, so the caller can check the
+// other changes before executing the action on it again.
+func (res *WorkPackage) ExecuteCustomAction(c *HalClient, action *CustomAction) (*WorkPackage, error) {
+	link := action.GetLink("executeImmediately")
+	if link == nil {
+		return nil, errors.New("No 'executeImmediately' Link")
+	}
+	wp, err := res.executeCustomAction(c, link)
+	if resErr, ok := err.(*Error); ok && resErr.ErrorIdentifier() == ErrorUpdateConflict {
+		// Reload to get the current `lockVersion`
+		selfRes, loadErr := res.GetLinkResource(c, "self")
+		if loadErr != nil {
+			return nil, err
+		}
+		current, ok := selfRes.(*WorkPackage)
+		if !ok {
+			return nil, err
+		}
+		return current, err
+	}
+	return wp, err
+}
+
+// Register Factories
+func init() {
+	resourceTypes["CustomAction"] = func() Resource {
+		return NewCustomAction()
+	}
+}
@@ -0,0 +1,78 @@
+package hal
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+//
+// Document
+//
+
+type Document struct {
+	ResourceObject
+}
+
+func NewDocument() *Document {
+	return &Document{
+		ResourceObject{
+			Type: "Document",
+		},
+	}
+}
+
+func (res *Document) Id() int {
+	return res.GetInt("id")
+}
+
+func (res *Document) Title() string {
+	return res.GetString("title")
+}
+
+func (res *Document) Description() *Formattable {
+	return res.GetFormattable("description")
+}
+
+func (res *Document) GetProject(c *HalClient) *Project {
+	// Get embedded project or load from a link
+	val := res.GetEmbeddedResource("project", c)
+	if p, ok := val.(*Project); ok {
+		return p
+	}
+	return nil
+}
+
+func (res *Document) GetAttachments(c *HalClient) *Collection {
+	return res.getAttachments(c)
+}
+
+func (res *Document) AddAttachment(c *HalClient, fileName, contentType, description string, r io.Reader) (*Attachment, error) {
+	return c.AddAttachment(res, fileName, contentType, description, r)
+}
+
+func (c *HalClient) GetDocuments(filters *Filters) (*Collection, error) {
+	return c.GetFilteredCollection(c.endpoint("/documents", "documents"), filters)
+}
+
+func (res *Project) GetDocuments(c *HalClient) (*Collection, error) {
+	return c.GetDocuments(NewFilters().Filter("project_id", "=", strconv.Itoa(res.Id())))
+}
+
+func (c *HalClient) GetDocument(id int) (*Document, error) {
+	res, err := c.Get(c.endpoint("/documents", "documents") + "/" + strconv.Itoa(id))
+	if err != nil {
+		return nil, err
+	}
+	if d, ok := res.(*Document); ok {
+		return d, nil
+	}
+	return nil, fmt.Errorf("Unknown resource type: %s", res.ResourceType())
+}
+
+// Register Factories
+func init() {
+	resourceTypes["Document"] = func() Resource {
+		return NewDocument()
+	}
+}
@@ -8,6 +8,10 @@ import (
 // Error
 //
 
+const (
+	ErrorUpdateConflict = "urn:openproject-org:api:v3:errors:UpdateConflict"
+)
+
 type Error struct {
 	ResourceObject
 }
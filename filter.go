@@ -3,6 +3,7 @@ package hal
 import (
 	"encoding/json"
 	"log"
+	"time"
 )
 
 //
@@ -31,7 +32,7 @@ func (f *Filters) MarshalJSON() ([]byte, error) {
 }
 
 func (f *Filters) String() string {
-	if len(f.FilterList) == 0 {
+	if f == nil || len(f.FilterList) == 0 {
 		return ""
 	}
 	if buf, err := json.Marshal(f); err != nil {
@@ -52,3 +53,8 @@ func (f *Filters) Filter(name string, operator string, values ...interface{}) *F
 	f.FilterList = append(f.FilterList, filter)
 	return f
 }
+
+// Filter a date field to the range `from` - `to` (inclusive).
+func (f *Filters) DateRange(name string, from time.Time, to time.Time) *Filters {
+	return f.Filter(name, "<>d", from.Format("2006-01-02"), to.Format("2006-01-02"))
+}
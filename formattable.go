@@ -1,11 +1,22 @@
 package hal
 
-import "errors"
+import (
+	"encoding/json"
+	"errors"
+	"html"
+	"strings"
+)
 
 //
 // Formattable
 //
 
+const (
+	FormatMarkdown = "markdown"
+	FormatPlain    = "plain"
+	FormatCustom   = "custom"
+)
+
 type Formattable struct {
 	Format string `json:"format"`
 	Raw    string `json:"raw"`
@@ -13,17 +24,32 @@ type Formattable struct {
 }
 
 func DecodeFormattable(data interface{}) (*Formattable, error) {
-	if data == nil {
+	switch v := data.(type) {
+	case nil:
 		return nil, nil
+	case *Formattable:
+		if v == nil {
+			return nil, nil
+		}
+		f := *v
+		return &f, nil
+	case Formattable:
+		return &v, nil
+	case map[string]interface{}:
+		f := &Formattable{}
+		if err := f.decodeMap(v); err != nil {
+			return nil, err
+		}
+		return f, nil
 	}
-	// convert to map
-	mData, ok := data.(map[string]interface{})
-	if !ok {
-		return nil, errors.New("Expected a map")
-	}
+	return nil, errors.New("Expected a map")
+}
 
-	f := &Formattable{}
+func (f *Formattable) decodeMap(mData map[string]interface{}) error {
 	for key, val := range mData {
+		if val == nil {
+			continue
+		}
 		s, ok := val.(string)
 		switch key {
 		case "format":
@@ -37,10 +63,10 @@ func DecodeFormattable(data interface{}) (*Formattable, error) {
 			continue
 		}
 		if !ok {
-			return nil, errors.New("Expected a string.")
+			return errors.New("Expected a string.")
 		}
 	}
-	return f, nil
+	return nil
 }
 
 func NewFormattable(format, raw, html string) *Formattable {
@@ -50,3 +76,57 @@ func NewFormattable(format, raw, html string) *Formattable {
 		Html:   html,
 	}
 }
+
+// Only `raw` is required when writing, the server renders `html`.
+func (f Formattable) MarshalJSON() ([]byte, error) {
+	data := make(map[string]interface{})
+	if f.Format != "" {
+		data["format"] = f.Format
+	}
+	data["raw"] = f.Raw
+	if f.Html != "" {
+		data["html"] = f.Html
+	}
+	return json.Marshal(data)
+}
+
+func (f *Formattable) UnmarshalJSON(data []byte) error {
+	var mData map[string]interface{}
+	if err := json.Unmarshal(data, &mData); err != nil {
+		return err
+	}
+	*f = Formattable{}
+	return f.decodeMap(mData)
+}
+
+// Get the text without markup, for chat messages and previews.
+func (f *Formattable) PlainText() string {
+	if f.Html != "" {
+		return HTMLToText(f.Html)
+	}
+	if f.Format == "markdown" {
+		return MarkdownToText(f.Raw)
+	}
+	return f.Raw
+}
+
+// Get the HTML with only the elements allowed by `policy` (defaults to
+// `DefaultSanitizePolicy`).  Without rendered HTML the plain text is
+// converted to paragraphs.
+func (f *Formattable) SanitizedHTML(policy *SanitizePolicy) string {
+	if policy == nil {
+		policy = DefaultSanitizePolicy()
+	}
+	if f.Html != "" {
+		return policy.Sanitize(f.Html)
+	}
+	var b strings.Builder
+	for _, para := range strings.Split(f.PlainText(), "\n\n") {
+		if para == "" {
+			continue
+		}
+		para = strings.Replace(html.EscapeString(para), "\n", "<br>", -1)
+		b.WriteString("<p>" + para + "</p>")
+	}
+	return b.String()
+}
@@ -0,0 +1,388 @@
+package hal
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+//
+// Local rendering of Formattable text
+//
+
+// Elements whose content is never shown.
+var hiddenElements = map[string]bool{
+	"script": true, "style": true, "head": true, "title": true, "template": true,
+	"noscript": true, "iframe": true, "object": true, "embed": true,
+}
+
+// Elements that start on a new line in plain text.
+var blockElements = map[string]bool{
+	"p": true, "div": true, "blockquote": true, "pre": true, "table": true,
+	"tr": true, "ul": true, "ol": true, "hr": true, "section": true,
+	"article": true, "figure": true, "figcaption": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+}
+
+// Elements without an end tag.
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true,
+	"img": true, "input": true, "link": true, "meta": true, "source": true,
+	"track": true, "wbr": true,
+}
+
+var spaceRun = regexp.MustCompile(`\s+`)
+
+type textBuilder struct {
+	strings.Builder
+}
+
+func (b *textBuilder) atLineStart() bool {
+	s := b.String()
+	return len(s) == 0 || s[len(s)-1] == '\n'
+}
+
+func (b *textBuilder) newline() {
+	if !b.atLineStart() {
+		b.WriteByte('\n')
+	}
+}
+
+func (b *textBuilder) paragraph() {
+	if b.Len() == 0 {
+		return
+	}
+	b.newline()
+	if !strings.HasSuffix(b.String(), "\n\n") {
+		b.WriteByte('\n')
+	}
+}
+
+var blankLines = regexp.MustCompile(`\n{3,}`)
+
+func cleanText(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	s = strings.Join(lines, "\n")
+	return strings.TrimSpace(blankLines.ReplaceAllString(s, "\n\n"))
+}
+
+type textLink struct {
+	href  string
+	start int
+}
+
+// Convert HTML to plain text.  Block elements become paragraphs, list items
+// are prefixed with "- " and link targets are added after the link text.
+func HTMLToText(s string) string {
+	z := html.NewTokenizer(strings.NewReader(s))
+	b := &textBuilder{}
+	hidden := 0
+	pre := 0
+	var links []textLink
+	for {
+		tt := z.Next()
+		switch tt {
+		case html.ErrorToken:
+			return cleanText(b.String())
+		case html.TextToken:
+			if hidden > 0 {
+				continue
+			}
+			text := string(z.Text())
+			if pre == 0 {
+				text = spaceRun.ReplaceAllString(text, " ")
+				if b.atLineStart() {
+					text = strings.TrimLeft(text, " ")
+				}
+			}
+			b.WriteString(text)
+		case html.StartTagToken, html.SelfClosingTagToken:
+			tok := z.Token()
+			name := tok.Data
+			if hiddenElements[name] {
+				if tt == html.StartTagToken && !voidElements[name] {
+					hidden++
+				}
+				continue
+			}
+			if hidden > 0 {
+				continue
+			}
+			switch {
+			case name == "br":
+				b.WriteByte('\n')
+			case name == "li":
+				b.newline()
+				b.WriteString("- ")
+			case name == "td" || name == "th":
+				if !b.atLineStart() {
+					b.WriteByte('\t')
+				}
+			case name == "img":
+				for _, a := range tok.Attr {
+					if a.Key == "alt" {
+						b.WriteString(a.Val)
+					}
+				}
+			case name == "a":
+				link := textLink{start: b.Len()}
+				for _, a := range tok.Attr {
+					if a.Key == "href" {
+						link.href = a.Val
+					}
+				}
+				links = append(links, link)
+			case blockElements[name]:
+				b.paragraph()
+				if name == "pre" {
+					pre++
+				}
+			}
+		case html.EndTagToken:
+			tok := z.Token()
+			name := tok.Data
+			if hiddenElements[name] {
+				if hidden > 0 {
+					hidden--
+				}
+				continue
+			}
+			if hidden > 0 {
+				continue
+			}
+			switch {
+			case name == "a" && len(links) > 0:
+				link := links[len(links)-1]
+				links = links[:len(links)-1]
+				text := strings.TrimSpace(b.String()[link.start:])
+				if u, err := url.Parse(link.href); err == nil && u.IsAbs() &&
+					text != link.href && text != strings.TrimPrefix(link.href, "mailto:") {
+					b.WriteString(" (" + link.href + ")")
+				}
+			case blockElements[name]:
+				if name == "pre" && pre > 0 {
+					pre--
+				}
+				b.paragraph()
+			case name == "li":
+				b.newline()
+			}
+		}
+	}
+}
+
+//
+// Markdown to text
+//
+
+var (
+	mdFence      = regexp.MustCompile("^\\s*(```|~~~)")
+	mdHeading    = regexp.MustCompile(`^\s{0,3}#{1,6}\s+(.*?)\s*#*\s*$`)
+	mdQuote      = regexp.MustCompile(`^\s{0,3}>\s?`)
+	mdBullet     = regexp.MustCompile(`^(\s*)[-*+]\s+`)
+	mdRule       = regexp.MustCompile(`^\s{0,3}([-*_]\s*){3,}$`)
+	mdImage      = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	mdLink       = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)[^)]*\)`)
+	mdCode       = regexp.MustCompile("`([^`]*)`")
+	mdStrong     = regexp.MustCompile(`\*\*([^*\s](?:[^*\n]*[^*\s])?)\*\*`)
+	mdStrongU    = regexp.MustCompile(`(^|\W)__([^_\s](?:[^_\n]*[^_\s])?)__(\W|$)`)
+	mdEmphasis   = regexp.MustCompile(`\*([^*\s](?:[^*\n]*[^*\s])?)\*`)
+	mdEmphasisU  = regexp.MustCompile(`(^|\W)_([^_\s](?:[^_\n]*[^_\s])?)_(\W|$)`)
+	mdStrike     = regexp.MustCompile(`~~([^~\s](?:[^~\n]*[^~\s])?)~~`)
+	mdInlineHTML = regexp.MustCompile(`</?[a-zA-Z][^>]*>`)
+)
+
+func markdownInline(line string) string {
+	// Keep code spans as-is, only strip the backticks.
+	codes := mdCode.FindAllStringSubmatch(line, -1)
+	line = mdCode.ReplaceAllString(line, "\x00")
+	line = mdImage.ReplaceAllString(line, "$1")
+	line = mdLink.ReplaceAllStringFunc(line, func(m string) string {
+		parts := mdLink.FindStringSubmatch(m)
+		if parts[1] == parts[2] {
+			return parts[1]
+		}
+		return parts[1] + " (" + parts[2] + ")"
+	})
+	line = mdInlineHTML.ReplaceAllString(line, "")
+	// Delimiters need a non-space character right inside them, so
+	// "2 * 3 * 4" isn't emphasis.
+	line = mdStrong.ReplaceAllString(line, "$1")
+	line = mdStrongU.ReplaceAllString(line, "$1$2$3")
+	line = mdEmphasis.ReplaceAllString(line, "$1")
+	line = mdEmphasisU.ReplaceAllString(line, "$1$2$3")
+	line = mdStrike.ReplaceAllString(line, "$1")
+	for _, code := range codes {
+		line = strings.Replace(line, "\x00", code[1], 1)
+	}
+	return line
+}
+
+// Convert markdown to plain text by removing the markup.
+func MarkdownToText(s string) string {
+	lines := strings.Split(strings.Replace(s, "\r\n", "\n", -1), "\n")
+	out := make([]string, 0, len(lines))
+	inFence := false
+	for _, line := range lines {
+		if mdFence.MatchString(line) {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			out = append(out, line)
+			continue
+		}
+		for mdQuote.MatchString(line) {
+			line = mdQuote.ReplaceAllString(line, "")
+		}
+		switch {
+		case mdRule.MatchString(line):
+			line = ""
+		case mdHeading.MatchString(line):
+			line = mdHeading.ReplaceAllString(line, "$1")
+		case mdBullet.MatchString(line):
+			line = mdBullet.ReplaceAllString(line, "$1- ")
+		}
+		out = append(out, markdownInline(line))
+	}
+	return cleanText(strings.Join(out, "\n"))
+}
+
+//
+// HTML sanitizer
+//
+
+type SanitizePolicy struct {
+	// Allowed elements and their allowed attributes.  The content of other
+	// elements is kept, unless it is a script, style, ...
+	Elements map[string][]string
+	// Allowed schemes for URLs in `href` and `src` attributes.  Relative
+	// URLs are always allowed.
+	URLSchemes []string
+}
+
+// A conservative policy for HTML emails.
+func DefaultSanitizePolicy() *SanitizePolicy {
+	p := &SanitizePolicy{
+		Elements: map[string][]string{
+			"a":   {"href", "title"},
+			"img": {"src", "alt", "title", "width", "height"},
+		},
+		URLSchemes: []string{"http", "https", "mailto"},
+	}
+	for _, name := range []string{
+		"p", "br", "hr", "div", "span", "b", "strong", "i", "em", "u", "s", "del",
+		"code", "pre", "blockquote", "ul", "ol", "li", "h1", "h2", "h3", "h4",
+		"h5", "h6", "table", "thead", "tbody", "tr", "th", "td",
+	} {
+		p.Elements[name] = nil
+	}
+	return p
+}
+
+var urlAttributes = map[string]bool{
+	"href": true, "src": true, "cite": true,
+}
+
+func (p *SanitizePolicy) allowedURL(val string) bool {
+	u, err := url.Parse(strings.TrimSpace(val))
+	if err != nil {
+		return false
+	}
+	if u.Scheme == "" {
+		return true
+	}
+	for _, scheme := range p.URLSchemes {
+		if strings.EqualFold(u.Scheme, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *SanitizePolicy) startTag(tok html.Token) string {
+	allowedAttrs := p.Elements[tok.Data]
+	var b strings.Builder
+	b.WriteString("<" + tok.Data)
+	for _, a := range tok.Attr {
+		allowed := false
+		for _, name := range allowedAttrs {
+			if a.Key == name {
+				allowed = true
+				break
+			}
+		}
+		if !allowed || (urlAttributes[a.Key] && !p.allowedURL(a.Val)) {
+			continue
+		}
+		b.WriteString(" " + a.Key + `="` + html.EscapeString(a.Val) + `"`)
+	}
+	b.WriteString(">")
+	return b.String()
+}
+
+// Remove all elements, attributes and URLs not allowed by the policy.  The
+// result is well-formed: all elements are closed.
+func (p *SanitizePolicy) Sanitize(s string) string {
+	z := html.NewTokenizer(strings.NewReader(s))
+	var b strings.Builder
+	var open []string
+	hidden := 0
+	for {
+		tt := z.Next()
+		switch tt {
+		case html.ErrorToken:
+			for i := len(open) - 1; i >= 0; i-- {
+				b.WriteString("</" + open[i] + ">")
+			}
+			return b.String()
+		case html.TextToken:
+			if hidden == 0 {
+				b.WriteString(html.EscapeString(string(z.Text())))
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			tok := z.Token()
+			if hiddenElements[tok.Data] {
+				if tt == html.StartTagToken && !voidElements[tok.Data] {
+					hidden++
+				}
+				continue
+			}
+			if _, ok := p.Elements[tok.Data]; !ok || hidden > 0 {
+				continue
+			}
+			b.WriteString(p.startTag(tok))
+			if voidElements[tok.Data] {
+				continue
+			}
+			if tt == html.SelfClosingTagToken {
+				b.WriteString("</" + tok.Data + ">")
+			} else {
+				open = append(open, tok.Data)
+			}
+		case html.EndTagToken:
+			tok := z.Token()
+			if hiddenElements[tok.Data] {
+				if hidden > 0 {
+					hidden--
+				}
+				continue
+			}
+			// Close the element and any elements left open inside it.
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i] == tok.Data {
+					for j := len(open) - 1; j >= i; j-- {
+						b.WriteString("</" + open[j] + ">")
+					}
+					open = open[:i]
+					break
+				}
+			}
+		}
+	}
+}
@@ -2,4 +2,7 @@ module github.com/lectio/go-json-hal
 
 go 1.12
 
-require github.com/SpirentOrion/iso8601duration.v2 v0.0.0-20190614070654-098d2a5d655d
+require (
+	github.com/SpirentOrion/iso8601duration.v2 v0.0.0-20190614070654-098d2a5d655d
+	golang.org/x/net v0.0.0-20190827160401-ba9fcec4b297
+)
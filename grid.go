@@ -0,0 +1,267 @@
+package hal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+//
+// GridWidget
+//
+
+type GridWidget struct {
+	Type        string                 `json:"_type"`
+	Id          int                    `json:"id,omitempty"`
+	Identifier  string                 `json:"identifier"`
+	StartRow    int                    `json:"startRow"`
+	EndRow      int                    `json:"endRow"`
+	StartColumn int                    `json:"startColumn"`
+	EndColumn   int                    `json:"endColumn"`
+	Options     map[string]interface{} `json:"options"`
+}
+
+func NewGridWidget(identifier string) *GridWidget {
+	return &GridWidget{
+		Type:       "GridWidget",
+		Identifier: identifier,
+		Options:    make(map[string]interface{}),
+	}
+}
+
+// Filters of a "work_package_query" widget (board column).
+func (w *GridWidget) Filters() *Filters {
+	filters := NewFilters()
+	if val, ok := w.Options["filters"]; ok {
+		if buf, err := json.Marshal(val); err == nil {
+			json.Unmarshal(buf, &filters.FilterList)
+		}
+	}
+	return filters
+}
+
+func (w *GridWidget) QueryId() int {
+	switch id := w.Options["queryId"].(type) {
+	case string:
+		n, _ := strconv.Atoi(id)
+		return n
+	case float64:
+		return int(id)
+	}
+	return 0
+}
+
+//
+// Grid
+//
+// Boards and dashboards are grids of widgets.
+//
+
+type Grid struct {
+	ResourceObject
+}
+
+func NewGrid() *Grid {
+	return &Grid{
+		ResourceObject{
+			Type: "Grid",
+		},
+	}
+}
+
+func (res *Grid) Id() int {
+	return res.GetInt("id")
+}
+
+func (res *Grid) Name() string {
+	return res.GetString("name")
+}
+
+func (res *Grid) SetName(name string) {
+	res.SetField("name", name)
+}
+
+func (res *Grid) RowCount() int {
+	return res.GetInt("rowCount")
+}
+
+func (res *Grid) ColumnCount() int {
+	return res.GetInt("columnCount")
+}
+
+func (res *Grid) Options() map[string]interface{} {
+	opts, _ := res.GetField("options").(map[string]interface{})
+	return opts
+}
+
+// The page the grid belongs to, e.g. "/projects/lectio/boards".
+func (res *Grid) Scope() string {
+	if l := res.GetLink("scope"); l != nil {
+		return l.Href
+	}
+	return ""
+}
+
+func (res *Grid) SetScope(scope string) {
+	res.AddLink("scope", *NewLink(scope))
+}
+
+func (res *Grid) Widgets() []*GridWidget {
+	var widgets []*GridWidget
+	if buf, err := json.Marshal(res.GetField("widgets")); err == nil {
+		json.Unmarshal(buf, &widgets)
+	}
+	return widgets
+}
+
+func (res *Grid) SetWidgets(widgets []*GridWidget) {
+	res.SetField("widgets", widgets)
+}
+
+// Board columns ordered from left to right.
+func (res *Grid) Columns() []*GridWidget {
+	var columns []*GridWidget
+	for _, w := range res.Widgets() {
+		if w.Identifier == "work_package_query" {
+			columns = append(columns, w)
+		}
+	}
+	sort.SliceStable(columns, func(i, j int) bool {
+		return columns[i].StartColumn < columns[j].StartColumn
+	})
+	return columns
+}
+
+// Board attributes that can be changed by moving a work package between
+// columns: attribute -> endpoint of the values.
+var boardAttributes = map[string]string{
+	"status":   "/statuses",
+	"version":  "/versions",
+	"assignee": "/users",
+}
+
+// Move a work package to a column of an action board (e.g. a status board)
+// by changing the column's attribute on the work package.
+func (res *Grid) MoveWorkPackage(c *HalClient, wp *WorkPackage, column *GridWidget) (*WorkPackage, error) {
+	attribute, _ := res.Options()["attribute"].(string)
+	if typ, _ := res.Options()["type"].(string); typ != "action" || boardAttributes[attribute] == "" {
+		return nil, errors.New("Only status, version and assignee boards are supported")
+	}
+	var value string
+	for _, filter := range column.Filters().FilterList {
+		if op, ok := filter[attribute]; ok && len(op.Values) == 1 {
+			value = fmt.Sprint(op.Values[0])
+		}
+	}
+	if value == "" {
+		return nil, fmt.Errorf("Column has no '%s' filter", attribute)
+	}
+
+	link := wp.GetLink("updateImmediately")
+	if link == nil {
+		return nil, errors.New("No 'updateImmediately' Link")
+	}
+	patch := NewWorkPackage()
+	patch.SetField("lockVersion", wp.LockVersion())
+	patch.AddLink(attribute, *NewLink(c.apiRootPath() + boardAttributes[attribute] + "/" + value))
+	newRes, err := c.Patch(link.Href, patch)
+	if err != nil {
+		return nil, err
+	}
+	if wp, ok := newRes.(*WorkPackage); ok {
+		return wp, nil
+	}
+	return nil, fmt.Errorf("Unknown resource type: %s", newRes.ResourceType())
+}
+
+//
+// Grids API
+//
+
+// List grids for a scope, e.g. "/projects/lectio/boards".
+func (c *HalClient) GetGrids(scope string) (*Collection, error) {
+	return c.GetFilteredCollection(c.endpoint("/grids", "grids"), NewFilters().Filter("scope", "=", scope))
+}
+
+func (c *HalClient) CreateGrid(g *Grid) (*Grid, error) {
+	res, err := c.Post(c.endpoint("/grids", "grids"), g)
+	if err != nil {
+		return nil, err
+	}
+	if g, ok := res.(*Grid); ok {
+		return g, nil
+	}
+	return nil, fmt.Errorf("Unknown resource type: %s", res.ResourceType())
+}
+
+func (res *Project) GetBoards(c *HalClient) (*Collection, error) {
+	return c.GetGrids("/projects/" + res.Identifier() + "/boards")
+}
+
+// Delete the column queries of a board that couldn't be created.
+func (c *HalClient) deleteBoardQueries(queries []*Query) {
+	for _, q := range queries {
+		if q.GetLink("delete") != nil {
+			q.Delete(c)
+		} else if l := q.GetLink("self"); l != nil {
+			if resp, err := c.Delete(l.Href); err == nil {
+				resp.Body.Close()
+			}
+		}
+	}
+}
+
+// Create a status board with a column for each status.  Each column shows
+// the results of a hidden query filtering on the column's status.  The
+// queries are deleted again if the board can't be created.
+func (c *HalClient) CreateBoard(project *Project, name string, statuses []*Status) (*Grid, error) {
+	widgets := make([]*GridWidget, 0, len(statuses))
+	queries := make([]*Query, 0, len(statuses))
+	for i, st := range statuses {
+		filters := NewFilters().Filter("status", "=", strconv.Itoa(st.Id()))
+		q := NewQuery()
+		q.SetName(st.Name())
+		q.SetHidden(true)
+		q.SetProject(project)
+		q.SetFilters(c, filters)
+		q, err := c.CreateQuery(q)
+		if err != nil {
+			c.deleteBoardQueries(queries)
+			return nil, err
+		}
+		queries = append(queries, q)
+
+		w := NewGridWidget("work_package_query")
+		w.StartRow, w.EndRow = 1, 2
+		w.StartColumn, w.EndColumn = i+1, i+2
+		w.Options["queryId"] = strconv.Itoa(q.Id())
+		w.Options["filters"] = filters.FilterList
+		widgets = append(widgets, w)
+	}
+
+	g := NewGrid()
+	g.SetName(name)
+	g.SetScope("/projects/" + project.Identifier() + "/boards")
+	g.SetField("rowCount", 1)
+	g.SetField("columnCount", len(statuses))
+	g.SetField("options", map[string]interface{}{
+		"type":      "action",
+		"attribute": "status",
+	})
+	g.SetWidgets(widgets)
+	board, err := c.CreateGrid(g)
+	if err != nil {
+		c.deleteBoardQueries(queries)
+		return nil, err
+	}
+	return board, nil
+}
+
+// Register Factories
+func init() {
+	resourceTypes["Grid"] = func() Resource {
+		return NewGrid()
+	}
+}
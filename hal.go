@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"strings"
 	"time"
 
 	duration "github.com/SpirentOrion/iso8601duration.v2"
@@ -35,6 +36,10 @@ type Link struct {
 	Method     string      `json:"method,omitempty"`
 	Payload    interface{} `json:"payload,omitempty"`
 	Identifier string      `json:"identifier,omitempty"`
+	Name       string      `json:"name,omitempty"`
+
+	// Extra form fields for direct (presigned) uploads.
+	FormFields map[string]string `json:"form_fields,omitempty"`
 }
 
 func NewLink(href string) *Link {
@@ -43,6 +48,20 @@ func NewLink(href string) *Link {
 	}
 }
 
+// An empty `Href` is encoded as null, e.g. `"version":{"href":null}` to
+// remove a work package from its version.
+func (l Link) MarshalJSON() ([]byte, error) {
+	type link Link
+	data := struct {
+		Href *string `json:"href"`
+		link
+	}{link: link(l)}
+	if l.Href != "" {
+		data.Href = &l.Href
+	}
+	return json.Marshal(data)
+}
+
 type Resource interface {
 	ResourceType() string
 	GetLink(string) *Link
@@ -61,8 +80,10 @@ type ResourceObject struct {
 	Links map[string]Link `json:"_links,omitempty"`
 
 	// Don't export these fields
-	embedded map[string]interface{}
-	fields   map[string]interface{}
+	linkLists map[string][]Link
+	curies    map[string]string
+	embedded  map[string]interface{}
+	fields    map[string]interface{}
 }
 
 func NewUnkownResource() *ResourceObject {
@@ -95,6 +116,27 @@ func (res *ResourceObject) SetField(field string, val interface{}) {
 	res.fields[field] = val
 }
 
+// Get a copy of a formatted text field.  Returns nil if the field is missing
+// or null.
+func (res *ResourceObject) GetFormattable(field string) *Formattable {
+	val, _ := res.getField(field)
+	f, err := DecodeFormattable(val)
+	if err != nil {
+		return nil
+	}
+	return f
+}
+
+// Set a formatted text field to a copy of `val`.  A nil `val` clears the
+// field.
+func (res *ResourceObject) SetFormattable(field string, val *Formattable) {
+	if val == nil {
+		res.SetField(field, nil)
+		return
+	}
+	res.SetField(field, *val)
+}
+
 func (res *ResourceObject) GetString(field string) string {
 	val, ok := res.getField(field)
 	if ok {
@@ -120,11 +162,26 @@ func (res *ResourceObject) GetInt(field string) int {
 	return 0
 }
 
+func (res *ResourceObject) GetBool(field string) bool {
+	val, ok := res.getField(field)
+	if ok {
+		if b, ok := val.(bool); ok {
+			return b
+		}
+	}
+	return false
+}
+
 func (res *ResourceObject) GetDateTime(field string) (time.Time, error) {
 	val := res.GetString(field)
 	return time.Parse(time.RFC3339, val)
 }
 
+func (res *ResourceObject) GetDate(field string) (time.Time, error) {
+	val := res.GetString(field)
+	return time.Parse("2006-01-02", val)
+}
+
 func (res *ResourceObject) GetDuration(field string) (time.Duration, error) {
 	val := res.GetString(field)
 	return duration.Parse(val)
@@ -190,20 +247,151 @@ func (res *ResourceObject) GetEmbeddedResourceList(name string) []Resource {
 	return nil
 }
 
+// Get a link by rel.  Namespaced rels can be given in compact ("op:foo") or
+// expanded form.
 func (res *ResourceObject) GetLink(name string) *Link {
-	if link, ok := res.Links[name]; ok {
-		return &link
+	for _, rel := range res.relNames(name) {
+		if link, ok := res.Links[rel]; ok {
+			return &link
+		}
 	}
 	return nil
 }
 
 func (res *ResourceObject) AddLink(name string, link Link) {
+	if name == "curies" {
+		// Curies are always an array.
+		res.SetLinks(name, []Link{link})
+		return
+	}
 	if res.Links == nil {
 		res.Links = make(map[string]Link)
 	}
+	res.removeLinks(name)
 	res.Links[name] = link
 }
 
+// Remove the links of a rel, stored under its compact or expanded name.
+func (res *ResourceObject) removeLinks(name string) {
+	for _, rel := range res.relNames(name) {
+		delete(res.Links, rel)
+		delete(res.linkLists, rel)
+	}
+}
+
+// Get an array of links (e.g. `roles`).  A single link is returned as an
+// array with one link.
+func (res *ResourceObject) GetLinks(name string) []Link {
+	for _, rel := range res.relNames(name) {
+		if links, ok := res.linkLists[rel]; ok {
+			return append([]Link(nil), links...)
+		}
+		if link, ok := res.Links[rel]; ok {
+			return []Link{link}
+		}
+	}
+	return nil
+}
+
+func (res *ResourceObject) SetLinks(name string, links []Link) {
+	if res.linkLists == nil {
+		res.linkLists = make(map[string][]Link)
+	}
+	res.removeLinks(name)
+	res.linkLists[name] = links
+	if name == "curies" {
+		res.resolveCuries()
+	}
+}
+
+//
+// CURIEs
+//
+// Compact URIs for namespaced link relations, e.g. `op:foo` with the curie
+// `{"name":"op","href":"https://example.com/rels/{rel}","templated":true}`.
+//
+
+// Add a curie.  `href` is a URI template with a `{rel}` placeholder.
+func (res *ResourceObject) AddCurie(name string, href string) {
+	curies := res.GetLinks("curies")
+	for i, curie := range curies {
+		if curie.Name == name {
+			curies = append(curies[:i], curies[i+1:]...)
+			break
+		}
+	}
+	curies = append(curies, Link{Name: name, Href: href, Templated: true})
+	res.SetLinks("curies", curies)
+}
+
+// Get the documentation URL for a namespaced rel.  Returns an empty string
+// if the rel doesn't use a known curie.
+func (res *ResourceObject) LinkDocumentation(rel string) string {
+	if prefix, ref, ok := splitCurie(rel); ok {
+		if href, ok := res.curies[prefix]; ok {
+			return strings.Replace(href, "{rel}", ref, -1)
+		}
+		return ""
+	}
+	if res.compactRel(rel) != "" {
+		return rel
+	}
+	return ""
+}
+
+func (res *ResourceObject) resolveCuries() {
+	res.curies = nil
+	for _, curie := range res.GetLinks("curies") {
+		if curie.Name == "" || curie.Href == "" {
+			continue
+		}
+		if res.curies == nil {
+			res.curies = make(map[string]string)
+		}
+		res.curies[curie.Name] = curie.Href
+	}
+}
+
+func splitCurie(rel string) (string, string, bool) {
+	idx := strings.Index(rel, ":")
+	if idx <= 0 || strings.HasPrefix(rel[idx+1:], "//") {
+		return "", "", false
+	}
+	return rel[:idx], rel[idx+1:], true
+}
+
+// Get the compact form of an expanded rel, or an empty string.
+func (res *ResourceObject) compactRel(rel string) string {
+	for name, href := range res.curies {
+		parts := strings.SplitN(href, "{rel}", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		if len(rel) > len(parts[0])+len(parts[1]) &&
+			strings.HasPrefix(rel, parts[0]) && strings.HasSuffix(rel, parts[1]) {
+			return name + ":" + rel[len(parts[0]):len(rel)-len(parts[1])]
+		}
+	}
+	return ""
+}
+
+// Get the names a rel can be stored under: the rel itself and its compact
+// or expanded form.
+func (res *ResourceObject) relNames(rel string) []string {
+	names := []string{rel}
+	if len(res.curies) == 0 {
+		return names
+	}
+	if prefix, ref, ok := splitCurie(rel); ok {
+		if href, ok := res.curies[prefix]; ok {
+			names = append(names, strings.Replace(href, "{rel}", ref, -1))
+		}
+	} else if compact := res.compactRel(rel); compact != "" {
+		names = append(names, compact)
+	}
+	return names
+}
+
 func (res *ResourceObject) GetLinkResource(c *HalClient, name string) (Resource, error) {
 	link := res.GetLink(name)
 	if link == nil {
@@ -218,17 +406,33 @@ func (res *ResourceObject) GetLinkResource(c *HalClient, name string) (Resource,
 	return linkRes, nil
 }
 
+func (res *ResourceObject) getLinkCollection(c *HalClient, name string) (*Collection, error) {
+	linkRes, err := res.GetLinkResource(c, name)
+	if err != nil {
+		return nil, err
+	}
+	// Make sure it is a Collection
+	if col, ok := linkRes.(*Collection); ok {
+		return col, nil
+	}
+	return nil, fmt.Errorf("Unknown resource type: %s", linkRes.ResourceType())
+}
+
 func (res *ResourceObject) Delete(c *HalClient) error {
 	link := res.GetLink("delete")
 	if link == nil {
 		return errors.New("No 'delete' Link")
 	}
 	// Delete this resource
-	_, err := c.Delete(link.Href)
+	resp, err := c.Delete(link.Href)
 	if err != nil {
 		// Failed to delete resource
 		return err
 	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return responseError(resp)
+	}
 	return nil
 }
 
@@ -257,8 +461,19 @@ func (res *ResourceObject) UnmarshalHAL(data []byte) error {
 
 func (res *ResourceObject) MarshalJSON() ([]byte, error) {
 	m := make(map[string]interface{})
-	m["_type"] = res.Type
-	if res.Links != nil {
+	if res.Type != "" {
+		m["_type"] = res.Type
+	}
+	if len(res.linkLists) > 0 {
+		links := make(map[string]interface{})
+		for k, v := range res.Links {
+			links[k] = v
+		}
+		for k, v := range res.linkLists {
+			links[k] = v
+		}
+		m["_links"] = links
+	} else if res.Links != nil {
 		m["_links"] = res.Links
 	}
 	if res.embedded != nil {
@@ -345,11 +560,25 @@ func (res *ResourceObject) decodeHAL(mData map[string]json.RawMessage) error {
 					}
 					res.Links[key] = link
 				case '[':
-					// TODO: handle array of links
+					var links []Link
+					if err := json.Unmarshal(val, &links); err != nil {
+						log.Printf(" -- Unmarshal error: %s", err)
+						return err
+					}
+					if res.linkLists == nil {
+						res.linkLists = make(map[string][]Link)
+					}
+					res.linkLists[key] = links
 				default:
 					log.Printf("---- Unknown Link value: [%s]", string(val))
 				}
 			}
+			// Curies are always written back as an array.
+			if curie, ok := res.Links["curies"]; ok {
+				res.SetLinks("curies", []Link{curie})
+			} else {
+				res.resolveCuries()
+			}
 		case "_embedded":
 			// Unmarshal map of arrays of RawMessages
 			var rawEmbedded map[string]json.RawMessage
@@ -379,6 +608,19 @@ func (res *ResourceObject) decodeHAL(mData map[string]json.RawMessage) error {
 	return nil
 }
 
+// Decode a resource from a plain JSON value (e.g. an object in an array field).
+func (res *ResourceObject) decodeMap(m map[string]interface{}) error {
+	buf, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	var mData map[string]json.RawMessage
+	if err := json.Unmarshal(buf, &mData); err != nil {
+		return err
+	}
+	return res.decodeHAL(mData)
+}
+
 func decodeResource(mData map[string]json.RawMessage, res Resource) (Resource, error) {
 	// Decode resource type
 	if typeRaw, ok := mData["_type"]; ok {
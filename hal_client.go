@@ -6,8 +6,12 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"mime"
 	"net/http"
 	"net/url"
+	"strings"
+	"sync"
 )
 
 type HalClient struct {
@@ -15,41 +19,98 @@ type HalClient struct {
 
 	base string
 
+	// API entry point
+	apiRoot  string
+	root     *Root
+	rootGen  int
+	rootLock sync.Mutex
+
+	// Feature detection cache
+	features    map[string]bool
+	featureLock sync.Mutex
+
 	// API Key Auth
 	apiKey *string
 }
 
 func NewHalClient(base string) *HalClient {
 	return &HalClient{
-		base: base,
+		base:    base,
+		apiRoot: "/api/v3",
 	}
 }
 
+// Change the path of the API entry point (default "/api/v3").
+func (c *HalClient) SetAPIRoot(path string) {
+	c.rootLock.Lock()
+	c.apiRoot = path
+	c.rootLock.Unlock()
+	c.resetDiscovery()
+}
+
+func (c *HalClient) apiRootPath() string {
+	c.rootLock.Lock()
+	defer c.rootLock.Unlock()
+	return c.apiRoot
+}
+
+// Forget the cached Root and detected features, e.g. after the user changed.
+func (c *HalClient) resetDiscovery() {
+	c.rootLock.Lock()
+	c.root = nil
+	c.rootGen++
+	c.rootLock.Unlock()
+
+	c.featureLock.Lock()
+	c.features = nil
+	c.featureLock.Unlock()
+}
+
 func (c *HalClient) SetAPIKey(key string) {
 	c.apiKey = &key
+	c.resetDiscovery()
+}
+
+// Resolve `path` against the base URL.  Absolute URLs (e.g. presigned
+// storage URLs) are used as-is and marked as external.
+func (c *HalClient) resolveURL(path string) (string, bool) {
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return path, !strings.HasPrefix(path, c.base+"/")
+	}
+	return c.base + path, false
 }
 
 func (c *HalClient) newRequest(method string, path string, body io.Reader) (*http.Request, error) {
-	req, err := http.NewRequest(method, c.base+path, body)
+	u, external := c.resolveURL(path)
+	req, err := http.NewRequest(method, u, body)
 	if err != nil {
 		return nil, err
 	}
-	if c.apiKey != nil {
+	// Never send the API key to other hosts.
+	if c.apiKey != nil && !external {
 		req.SetBasicAuth("apikey", *c.apiKey)
 	}
 	return req, nil
 }
 
-func (c *HalClient) newRequestJSON(method string, path string, body io.Reader) (*http.Request, error) {
+// Create a request with a body of any content type that expects a JSON
+// response.
+func (c *HalClient) newRequestContent(method string, path string, contentType string, body io.Reader) (*http.Request, error) {
 	req, err := c.newRequest(method, path, body)
 	if err != nil {
 		return nil, err
 	}
 	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Content-Type", "application/json")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
 	return req, nil
 }
 
+func (c *HalClient) newRequestJSON(method string, path string, body io.Reader) (*http.Request, error) {
+	return c.newRequestContent(method, path, "application/json", body)
+}
+
 func (c *HalClient) doRequest(req *http.Request) (Resource, error) {
 	resp, err := c.Do(req)
 	if err != nil {
@@ -69,18 +130,90 @@ func (c *HalClient) doRequest(req *http.Request) (Resource, error) {
 	return res, nil
 }
 
-func (c *HalClient) GetFile(path string) (io.Reader, error) {
+//
+// File download
+//
+
+type FileReader struct {
+	io.ReadCloser
+
+	ContentType string
+	// Length of the body, -1 if unknown.
+	ContentLength int64
+	// File name from the `Content-Disposition` header.
+	FileName string
+	// Position in the file where the body starts.
+	Offset int64
+}
+
+// Convert a failed response into an `error`, using the HAL `Error` resource
+// when the server sent one.
+func responseError(resp *http.Response) error {
+	ct, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if ct == "application/json" || ct == "application/hal+json" {
+		if res, err := Decode(resp.Body); err == nil {
+			if err := res.IsError(); err != nil {
+				return err
+			}
+		}
+	}
+	return fmt.Errorf("Unexpected HTTP status: %s", resp.Status)
+}
+
+func (c *HalClient) GetFile(path string) (*FileReader, error) {
+	return c.GetFileRange(path, 0)
+}
+
+// Download a file starting at `offset`, for resuming interrupted downloads.
+// The caller must close the returned reader.
+func (c *HalClient) GetFileRange(path string, offset int64) (*FileReader, error) {
 	req, err := c.newRequest("GET", path, nil)
 	if err != nil {
 		return nil, err
 	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
 
 	resp, err := c.Do(req)
 	if err != nil {
 		return nil, err
 	}
 
-	return resp.Body, nil
+	f := &FileReader{
+		ReadCloser:    resp.Body,
+		ContentType:   resp.Header.Get("Content-Type"),
+		ContentLength: resp.ContentLength,
+	}
+	if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil {
+		f.FileName = params["filename"]
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		if offset > 0 {
+			// Server ignored the Range header, skip to the offset.
+			if _, err := io.CopyN(ioutil.Discard, resp.Body, offset); err != nil {
+				resp.Body.Close()
+				return nil, err
+			}
+			if f.ContentLength >= 0 {
+				f.ContentLength -= offset
+			}
+			f.Offset = offset
+		}
+	case http.StatusPartialContent:
+		var start, end int64
+		if _, err := fmt.Sscanf(resp.Header.Get("Content-Range"), "bytes %d-%d/", &start, &end); err != nil {
+			resp.Body.Close()
+			return nil, fmt.Errorf("Invalid Content-Range: %s", resp.Header.Get("Content-Range"))
+		}
+		f.Offset = start
+	default:
+		defer resp.Body.Close()
+		return nil, responseError(resp)
+	}
+	return f, nil
 }
 
 func (c *HalClient) Get(path string) (Resource, error) {
@@ -115,6 +248,15 @@ func (c *HalClient) Post(path string, res Resource) (Resource, error) {
 	}
 }
 
+// Post a non-JSON body (e.g. "text/plain").
+func (c *HalClient) PostContent(path string, contentType string, body io.Reader) (Resource, error) {
+	if req, err := c.newRequestContent("POST", path, contentType, body); err != nil {
+		return nil, err
+	} else {
+		return c.doRequest(req)
+	}
+}
+
 func (c *HalClient) Patch(path string, res Resource) (Resource, error) {
 	// encode resource as JSON for Post body.
 	body, err := json.Marshal(res)
@@ -157,7 +299,60 @@ func (c *HalClient) LinkGet(link *Link) (Resource, error) {
 	return c.Get(link.Href)
 }
 
-func (c *HalClient) LinkGetFile(link *Link) (io.Reader, error) {
+// Follow an action link (e.g. `lock`) using the link's method, sending `res`
+// as the request body if it isn't nil.
+func (c *HalClient) LinkRequest(link *Link, res Resource) (Resource, error) {
+	if link == nil {
+		return nil, errors.New("nil Link")
+	}
+	method := strings.ToUpper(link.Method)
+	if method == "" {
+		method = "GET"
+	}
+	var body io.Reader
+	if res != nil {
+		buf, err := json.Marshal(res)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewBuffer(buf)
+	}
+	req, err := c.newRequestJSON(method, link.Href, body)
+	if err != nil {
+		return nil, err
+	}
+	return c.doRequest(req)
+}
+
+// Send a request that doesn't return a resource (e.g. "204 No Content").
+func (c *HalClient) doAction(req *http.Request) error {
+	resp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return responseError(resp)
+	}
+	return nil
+}
+
+func (c *HalClient) linkAction(link *Link) error {
+	if link == nil {
+		return errors.New("nil Link")
+	}
+	method := strings.ToUpper(link.Method)
+	if method == "" {
+		method = "POST"
+	}
+	req, err := c.newRequestJSON(method, link.Href, nil)
+	if err != nil {
+		return err
+	}
+	return c.doAction(req)
+}
+
+func (c *HalClient) LinkGetFile(link *Link) (*FileReader, error) {
 	if link == nil {
 		return nil, errors.New("nil Link")
 	}
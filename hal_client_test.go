@@ -1,10 +1,16 @@
 package hal
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 const (
@@ -66,6 +72,7 @@ func newTestServer() *testServer {
 	}
 	// Create test server
 	server := httptest.NewServer(ts.router)
+	ts.server = server
 	// Connect client to test server
 	ts.client = NewHalClient(server.URL)
 
@@ -174,3 +181,1126 @@ func TestHalClient_Unauthorized(t *testing.T) {
 		t.Errorf("Expected unauthorized response: %v.", res)
 	}
 }
+
+func TestHalClient_UploadAttachment(t *testing.T) {
+	ts := newTestServer()
+	defer ts.Close()
+
+	ts.client.SetAPIKey(testAPIKey)
+
+	ts.router.HandleFunc("/api/v3/work_packages/1/attachments", func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != "POST" {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		mr, err := req.MultipartReader()
+		if err != nil {
+			halErrorHandler(w, http.StatusBadRequest,
+				"urn:openproject-org:api:v3:errors:InvalidRequestBody", err.Error())
+			return
+		}
+		// metadata part
+		part, err := mr.NextPart()
+		if err != nil || part.FormName() != "metadata" {
+			t.Errorf("Expected 'metadata' part: %v", err)
+			return
+		}
+		var meta struct {
+			FileName    string `json:"fileName"`
+			Description struct {
+				Raw string `json:"raw"`
+			} `json:"description"`
+		}
+		if err := json.NewDecoder(part).Decode(&meta); err != nil {
+			t.Errorf("Failed to decode metadata: %v", err)
+		}
+		// file part
+		part, err = mr.NextPart()
+		if err != nil || part.FormName() != "file" {
+			t.Errorf("Expected 'file' part: %v", err)
+			return
+		}
+		data, _ := ioutil.ReadAll(part)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"_type":"Attachment","id":7,"fileName":%q,"fileSize":%d,
+			"contentType":%q,"description":{"format":"plain","raw":%q,"html":""},
+			"_links":{"self":{"href":"/api/v3/attachments/7"}}}`,
+			part.FileName(), len(data), part.Header.Get("Content-Type"), meta.Description.Raw)
+	})
+
+	wp := NewWorkPackage()
+	wp.AddLink("addAttachment", Link{Href: "/api/v3/work_packages/1/attachments", Method: "post"})
+
+	att, err := wp.AddAttachment(ts.client, "notes.txt", "text/plain", "Some notes",
+		strings.NewReader("hello world"))
+	if err != nil {
+		t.Fatalf("HalClient failed to upload attachment: %v.", err)
+	}
+	if att.FileName() != "notes.txt" || att.FileSize() != 11 || att.ContentType() != "text/plain" {
+		t.Errorf("Unexpected attachment: %s %d %s", att.FileName(), att.FileSize(), att.ContentType())
+	}
+	if d := att.Description(); d == nil || d.Raw != "Some notes" {
+		t.Errorf("Unexpected attachment description: %+v", d)
+	}
+
+	// Resources without an `addAttachment` link can't have attachments.
+	if _, err := ts.client.AddAttachment(NewProject(), "x", "", "", strings.NewReader("")); err == nil {
+		t.Errorf("Expected error for missing 'addAttachment' link.")
+	}
+}
+
+func TestHalClient_DirectUploadAttachment(t *testing.T) {
+	ts := newTestServer()
+	defer ts.Close()
+
+	ts.client.SetAPIKey(testAPIKey)
+
+	// Stand-in for the object storage.
+	var stored []byte
+	storage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if _, _, ok := req.BasicAuth(); ok {
+			t.Errorf("API key sent to storage.")
+		}
+		if req.ContentLength <= 0 {
+			t.Errorf("Expected upload with known length.")
+		}
+		if err := req.ParseMultipartForm(1 << 20); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if req.FormValue("key") != "uploads/9/report.csv" || req.FormValue("policy") != "secret" {
+			http.Error(w, "bad form fields", http.StatusForbidden)
+			return
+		}
+		f, _, err := req.FormFile("file")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		stored, _ = ioutil.ReadAll(f)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer storage.Close()
+
+	ts.router.HandleFunc("/api/v3/work_packages/1/attachments/prepare", func(w http.ResponseWriter, req *http.Request) {
+		var meta struct {
+			FileName string `json:"fileName"`
+			FileSize int    `json:"fileSize"`
+		}
+		if err := json.NewDecoder(req.Body).Decode(&meta); err != nil || meta.FileSize != 6 {
+			t.Errorf("Bad prepare metadata: %+v %v", meta, err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"_type":"Attachment","id":9,"fileName":%q,"status":"prepared",
+			"_links":{
+				"self":{"href":"/api/v3/attachments/9"},
+				"addAttachment":{"href":%q,"method":"post",
+					"form_fields":{"key":"uploads/9/report.csv","policy":"secret"}},
+				"completeUpload":{"href":"/api/v3/attachments/9/uploaded"}
+			}}`, meta.FileName, storage.URL+"/bucket")
+	})
+	ts.addStatic("/api/v3/attachments/9/uploaded", `{"_type":"Attachment","id":9,
+		"fileName":"report.csv","fileSize":6,"status":"uploaded",
+		"_links":{"self":{"href":"/api/v3/attachments/9"}}}`, true)
+
+	wp := NewWorkPackage()
+	if SupportsDirectUploads(wp) {
+		t.Errorf("Direct uploads detected without a 'prepareAttachment' link.")
+	}
+	wp.AddLink("prepareAttachment", Link{Href: "/api/v3/work_packages/1/attachments/prepare", Method: "post"})
+	if !SupportsDirectUploads(wp) {
+		t.Errorf("Direct uploads not detected.")
+	}
+
+	att, err := wp.AddAttachment(ts.client, "report.csv", "text/csv", "", strings.NewReader("a,b,c\n"))
+	if err != nil {
+		t.Fatalf("HalClient failed direct upload: %v.", err)
+	}
+	if att.Id() != 9 || att.Status() != "uploaded" {
+		t.Errorf("Unexpected attachment: %d %s", att.Id(), att.Status())
+	}
+	if string(stored) != "a,b,c\n" {
+		t.Errorf("Storage received wrong content: %q", stored)
+	}
+}
+
+func TestHalClient_Download(t *testing.T) {
+	ts := newTestServer()
+	defer ts.Close()
+
+	ts.client.SetAPIKey(testAPIKey)
+
+	content := "The quick brown fox jumps over the lazy dog"
+	ts.router.HandleFunc("/attachments/1/fox.txt", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("Content-Disposition", `attachment; filename="fox.txt"`)
+		http.ServeContent(w, req, "fox.txt", time.Time{}, strings.NewReader(content))
+	})
+
+	newAttachment := func(hash string) *Attachment {
+		att := NewAttachment()
+		att.SetField("digest", map[string]interface{}{"algorithm": "md5", "hash": hash})
+		att.AddLink("downloadLocation", Link{Href: "/attachments/1/fox.txt"})
+		return att
+	}
+
+	// Valid digest
+	f, err := newAttachment("9e107d9d372bb6826bd81d3542a419d6").Download(ts.client)
+	if err != nil {
+		t.Fatalf("HalClient failed to download file: %v.", err)
+	}
+	data, err := ioutil.ReadAll(f)
+	f.Close()
+	if err != nil || string(data) != content {
+		t.Errorf("Download failed: %q %v", data, err)
+	}
+	if f.FileName != "fox.txt" || f.ContentType != "text/plain" || f.ContentLength != int64(len(content)) {
+		t.Errorf("Unexpected file metadata: %+v", f)
+	}
+
+	// Corrupted file
+	f, err = newAttachment("00000000000000000000000000000000").Download(ts.client)
+	if err != nil {
+		t.Fatalf("HalClient failed to download file: %v.", err)
+	}
+	_, err = ioutil.ReadAll(f)
+	f.Close()
+	if err != ErrDigestMismatch {
+		t.Errorf("Expected digest mismatch, got: %v", err)
+	}
+
+	// Resume
+	f, err = newAttachment("").DownloadFrom(ts.client, 40)
+	if err != nil {
+		t.Fatalf("HalClient failed to resume download: %v.", err)
+	}
+	data, _ = ioutil.ReadAll(f)
+	f.Close()
+	if string(data) != "dog" || f.Offset != 40 {
+		t.Errorf("Resume failed: %q at %d", data, f.Offset)
+	}
+
+	// Missing file
+	if _, err := ts.client.GetFile("/attachments/2/missing.txt"); err == nil {
+		t.Errorf("Expected error for missing file.")
+	}
+	// HAL errors
+	if _, err := NewHalClient(ts.server.URL).GetFile("/api/v3/my_preferences"); err == nil {
+		t.Errorf("Expected error for unauthorized download.")
+	}
+}
+
+func TestWorkPackage_DownloadAttachments(t *testing.T) {
+	ts := newTestServer()
+	defer ts.Close()
+
+	ts.client.SetAPIKey(testAPIKey)
+
+	files := map[string]string{
+		"/attachments/1": "first report",
+		"/attachments/2": "second report",
+		"/attachments/3": "evil",
+	}
+	for href, content := range files {
+		content := content
+		ts.router.HandleFunc(href, func(w http.ResponseWriter, req *http.Request) {
+			fmt.Fprint(w, content)
+		})
+	}
+
+	res, err := Unmarshal([]byte(`{"_type":"WorkPackage","id":1,
+		"_embedded":{"attachments":{"_type":"Collection","total":3,"count":3,
+			"_embedded":{"elements":[
+				{"_type":"Attachment","id":2,"fileName":"report.txt","fileSize":13,
+					"_links":{"downloadLocation":{"href":"/attachments/2"}}},
+				{"_type":"Attachment","id":1,"fileName":"report.txt","fileSize":12,
+					"digest":{"algorithm":"md5","hash":"d3c5b8ea0b5d8a5a9c5d5b4b44c0d6e8"},
+					"_links":{"downloadLocation":{"href":"/attachments/1"}}},
+				{"_type":"Attachment","id":3,"fileName":"../evil:name.txt","fileSize":4,
+					"_links":{"downloadLocation":{"href":"/attachments/3"}}}
+			]}
+		}}}`))
+	if err != nil {
+		t.Fatalf("Failed to parse WorkPackage: %v", err)
+	}
+	wp := res.(*WorkPackage)
+
+	dir, err := ioutil.TempDir("", "hal-attachments")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// A previous backup of the second attachment.
+	if err := ioutil.WriteFile(filepath.Join(dir, "report (2).txt"), []byte("second report"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := wp.DownloadAttachments(ts.client, dir, &DownloadOptions{Concurrency: 2})
+	if err == nil {
+		t.Errorf("Expected digest error for attachment 1.")
+	}
+	if len(report) != 3 {
+		t.Fatalf("Wrong report length: %d", len(report))
+	}
+	expect := []struct {
+		name   string
+		status DownloadStatus
+	}{
+		{"report (2).txt", DownloadSkipped},
+		{"report.txt", DownloadFailed},
+		{"evil_name.txt", DownloadOK},
+	}
+	for i, e := range expect {
+		r := report[i]
+		if filepath.Base(r.Path) != e.name || r.Status != e.status {
+			t.Errorf("Attachment %d: got %s/%s, expected %s/%s (%v)",
+				r.Attachment.Id(), filepath.Base(r.Path), r.Status, e.name, e.status, r.Err)
+		}
+	}
+	// Failed downloads must not leave files behind.
+	if _, err := os.Stat(filepath.Join(dir, "report.txt")); !os.IsNotExist(err) {
+		t.Errorf("Failed download left a file behind.")
+	}
+}
+
+func TestHalClient_UserAdmin(t *testing.T) {
+	ts := newTestServer()
+	defer ts.Close()
+
+	ts.client.SetAPIKey(testAPIKey)
+
+	status := UserStatusActive
+	userJSON := func() string {
+		return fmt.Sprintf(`{"_type":"User","id":4,"login":"test1","name":"test1 tester",
+			"status":%q,"admin":false,"language":"en",
+			"_links":{"self":{"href":"/api/v3/users/4"},
+				"lock":{"href":"/api/v3/users/4/lock","method":"post"},
+				"unlock":{"href":"/api/v3/users/4/lock","method":"delete"}}}`, status)
+	}
+	ts.router.HandleFunc("/api/v3/users/4/lock", func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case "POST":
+			status = UserStatusLocked
+		case "DELETE":
+			status = UserStatusActive
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, userJSON())
+	})
+	ts.router.HandleFunc("/api/v3/users", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if !strings.Contains(req.URL.Query().Get("filters"), `"login"`) {
+			t.Errorf("Expected login filter: %s", req.URL.RawQuery)
+		}
+		fmt.Fprintf(w, `{"_type":"Collection","total":1,"count":1,"_embedded":{"elements":[%s]}}`, userJSON())
+	})
+
+	u, err := ts.client.FindUserByLogin("test1")
+	if err != nil {
+		t.Fatalf("HalClient failed to find user: %v.", err)
+	}
+	if u, err = u.Lock(ts.client); err != nil || u.Status() != UserStatusLocked {
+		t.Errorf("Failed to lock user: %v", err)
+	}
+	if u, err = u.Unlock(ts.client); err != nil || u.Status() != UserStatusActive {
+		t.Errorf("Failed to unlock user: %v", err)
+	}
+	if _, err := ts.client.FindUserByLogin("missing"); err == nil {
+		t.Errorf("Expected error for unknown login.")
+	}
+}
+
+func TestHalClient_RootDiscovery(t *testing.T) {
+	ts := newTestServer()
+	defer ts.Close()
+
+	ts.client.SetAPIKey(testAPIKey)
+	ts.client.SetAPIRoot("/op/api/v3")
+
+	rootRequests := 0
+	ts.router.HandleFunc("/op/api/v3", func(w http.ResponseWriter, req *http.Request) {
+		rootRequests++
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"_type":"Root","instanceName":"Lectio","coreVersion":"12.5.0",
+			"_links":{"self":{"href":"/op/api/v3"},
+				"timeEntries":{"href":"/op/api/v3/time_entries"},
+				"user":{"href":"/op/api/v3/users/4","title":"test1 tester"}}}`)
+	})
+	ts.router.HandleFunc("/op/api/v3/time_entries", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"_type":"TimeEntry","id":1,"hours":"PT1H","spentOn":"2019-09-02",
+			"_links":{"self":{"href":"/op/api/v3/time_entries/1"}}}`)
+	})
+	ts.addStatic("/op/api/v3/users/4", `{"_type":"User","id":4,"login":"test1",
+		"_links":{"self":{"href":"/op/api/v3/users/4"}}}`, true)
+
+	root, err := ts.client.Root()
+	if err != nil {
+		t.Fatalf("HalClient failed to get Root: %v.", err)
+	}
+	if root.InstanceName() != "Lectio" {
+		t.Errorf("Unexpected instance name: %s", root.InstanceName())
+	}
+
+	wp := NewWorkPackage()
+	te := NewTimeEntry()
+	te.SetHours(time.Hour)
+	if _, err := wp.AddTimeEntry(ts.client, te); err != nil {
+		t.Errorf("Failed to add time entry at discovered endpoint: %v", err)
+	}
+	if me, err := ts.client.Me(); err != nil || me.Id() != 4 {
+		t.Errorf("Failed to get current user from Root link: %v", err)
+	}
+	if rootRequests != 1 {
+		t.Errorf("Root should be cached: %d requests", rootRequests)
+	}
+}
+
+func TestHalClient_Configuration(t *testing.T) {
+	ts := newTestServer()
+	defer ts.Close()
+
+	conf, err := ts.client.Configuration()
+	if err != nil {
+		t.Fatalf("HalClient failed to get Configuration: %v.", err)
+	}
+	if conf.MaximumAttachmentFileSize() != 5242880 {
+		t.Errorf("Unexpected maximum attachment size: %d", conf.MaximumAttachmentFileSize())
+	}
+	if opts := conf.PerPageOptions(); len(opts) != 2 || opts[1] != 100 {
+		t.Errorf("Unexpected per page options: %v", opts)
+	}
+}
+
+func TestHalClient_Supports(t *testing.T) {
+	ts := newTestServer()
+	defer ts.Close()
+
+	ts.addStatic("/api/v3", `{"_type":"Root","_links":{
+		"self":{"href":"/api/v3"},
+		"notifications":{"href":"/api/v3/notifications"}}}`, false)
+	ts.router.HandleFunc("/api/v3/days/week", func(w http.ResponseWriter, req *http.Request) {
+		halErrorHandler(w, http.StatusForbidden,
+			"urn:openproject-org:api:v3:errors:MissingPermission", "Forbidden")
+	})
+
+	tests := map[string]bool{
+		FeatureNotifications: true,  // Root link
+		FeatureDays:          true,  // Endpoint exists, but is forbidden
+		FeatureFileLinks:     false, // Endpoint not found
+	}
+	for name, expected := range tests {
+		supported, err := ts.client.Supports(name)
+		if err != nil {
+			t.Errorf("Failed to detect feature '%s': %v", name, err)
+		}
+		if supported != expected {
+			t.Errorf("Feature '%s': got %v, expected %v", name, supported, expected)
+		}
+	}
+}
+
+func TestHalClient_Notifications(t *testing.T) {
+	ts := newTestServer()
+	defer ts.Close()
+
+	ts.client.SetAPIKey(testAPIKey)
+
+	var actions []string
+	for _, path := range []string{"/api/v3/notifications/1/read_ian", "/api/v3/notifications/read_ian"} {
+		ts.router.HandleFunc(path, func(w http.ResponseWriter, req *http.Request) {
+			actions = append(actions, req.Method+" "+req.URL.Path+" "+req.URL.Query().Get("filters"))
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+	ts.addStatic("/api/v3/notifications", `{"_type":"Collection","total":1,"count":1,
+		"_embedded":{"elements":[{"_type":"Notification","id":1,"reason":"mentioned","readIAN":false,
+			"_embedded":{"actor":{"_type":"User","id":5,"name":"Other User"}},
+			"_links":{"self":{"href":"/api/v3/notifications/1"},
+				"readIAN":{"href":"/api/v3/notifications/1/read_ian","method":"post"},
+				"resource":{"href":"/api/v3/work_packages/3","title":"Fix bug"}}}]}}`, true)
+
+	col, err := ts.client.GetNotifications(NewFilters().Filter("readIAN", "=", "f"))
+	if err != nil {
+		t.Fatalf("HalClient failed to get notifications: %v.", err)
+	}
+	n, ok := col.Items()[0].(*Notification)
+	if !ok {
+		t.Fatalf("Expected Notification.")
+	}
+	if n.Reason() != NotificationReasonMentioned || n.GetActor(nil).Name() != "Other User" ||
+		n.ResourceLink().Href != "/api/v3/work_packages/3" {
+		t.Errorf("Unexpected notification: %s", n.Reason())
+	}
+	if err := n.MarkRead(ts.client); err != nil || !n.IsRead() {
+		t.Errorf("Failed to mark notification read: %v", err)
+	}
+	if err := n.MarkUnread(ts.client); err == nil {
+		t.Errorf("Expected error for missing 'unreadIAN' link.")
+	}
+	if err := ts.client.MarkAllNotificationsRead(NewFilters().Filter("project", "=", "3")); err != nil {
+		t.Errorf("Failed to mark all notifications read: %v", err)
+	}
+	expected := []string{
+		"POST /api/v3/notifications/1/read_ian ",
+		`POST /api/v3/notifications/read_ian [{"project":{"operator":"=","values":["3"]}}]`,
+	}
+	if fmt.Sprint(actions) != fmt.Sprint(expected) {
+		t.Errorf("Unexpected requests: %q", actions)
+	}
+}
+
+func TestHalClient_TimeEntryActivities(t *testing.T) {
+	ts := newTestServer()
+	defer ts.Close()
+
+	ts.client.SetAPIKey(testAPIKey)
+
+	ts.router.HandleFunc("/api/v3/time_entries/form", func(w http.ResponseWriter, req *http.Request) {
+		var body struct {
+			Links map[string]Link `json:"_links"`
+		}
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil || body.Links["project"].Href != "/api/v3/projects/3" {
+			t.Errorf("Expected project link in form request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"_type":"Form","_embedded":{
+			"payload":{"_type":"TimeEntry","_links":{"project":{"href":"/api/v3/projects/3"}}},
+			"schema":{"_type":"Schema",
+				"spentOn":{"type":"Date","name":"Date","required":true,"hasDefault":false,"writable":true},
+				"activity":{"type":"TimeEntriesActivity","name":"Activity","required":true,"writable":true,
+					"_embedded":{"allowedValues":[
+						{"_type":"TimeEntriesActivity","id":1,"name":"Management",
+							"_links":{"self":{"href":"/api/v3/time_entries/activities/1"}}},
+						{"_type":"TimeEntriesActivity","id":3,"name":"Development",
+							"_links":{"self":{"href":"/api/v3/time_entries/activities/3"}}}
+					]},
+					"_links":{"allowedValues":[]}}
+			},
+			"validationErrors":{"hours":{"_type":"Error","errorIdentifier":"urn:openproject-org:api:v3:errors:PropertyConstraintViolation","message":"Hours can't be blank."}}
+		}}`)
+	})
+
+	project := NewProject()
+	project.AddLink("self", *NewLink("/api/v3/projects/3"))
+	activities, err := ts.client.GetTimeEntryActivities(project)
+	if err != nil {
+		t.Fatalf("HalClient failed to get activities: %v.", err)
+	}
+	if len(activities) != 2 {
+		t.Fatalf("Wrong number of activities: %d", len(activities))
+	}
+	act := FindTimeEntryActivity(activities, "development")
+	if act == nil || act.Id() != 3 {
+		t.Fatalf("Failed to find activity by name.")
+	}
+	te := NewTimeEntry()
+	te.SetActivity(act)
+	if l := te.GetLink("activity"); l == nil || l.Href != "/api/v3/time_entries/activities/3" {
+		t.Errorf("Activity link not set: %+v", l)
+	}
+}
+
+func TestGrid_MoveWorkPackage(t *testing.T) {
+	ts := newTestServer()
+	defer ts.Close()
+
+	ts.client.SetAPIKey(testAPIKey)
+
+	ts.router.HandleFunc("/api/v3/work_packages/3", func(w http.ResponseWriter, req *http.Request) {
+		var patch struct {
+			LockVersion int             `json:"lockVersion"`
+			Links       map[string]Link `json:"_links"`
+		}
+		if err := json.NewDecoder(req.Body).Decode(&patch); err != nil || req.Method != "PATCH" {
+			t.Errorf("Expected PATCH: %v", err)
+		}
+		if patch.LockVersion != 2 || patch.Links["status"].Href != "/api/v3/statuses/7" {
+			t.Errorf("Unexpected patch: %+v", patch)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"_type":"WorkPackage","id":3,"lockVersion":3,
+			"_links":{"status":{"href":"/api/v3/statuses/7","title":"In progress"}}}`)
+	})
+
+	res, err := Unmarshal([]byte(`{"_type":"Grid","id":1,"name":"Kanban","rowCount":1,"columnCount":2,
+		"options":{"type":"action","attribute":"status"},
+		"widgets":[
+			{"_type":"GridWidget","id":2,"identifier":"work_package_query","startRow":1,"endRow":2,
+				"startColumn":2,"endColumn":3,
+				"options":{"queryId":"12","filters":[{"status":{"operator":"=","values":["7"]}}]}},
+			{"_type":"GridWidget","id":1,"identifier":"work_package_query","startRow":1,"endRow":2,
+				"startColumn":1,"endColumn":2,
+				"options":{"queryId":"11","filters":[{"status":{"operator":"=","values":["1"]}}]}}
+		],
+		"_links":{"scope":{"href":"/projects/lectio/boards"}}}`))
+	if err != nil {
+		t.Fatalf("Failed to parse Grid: %v", err)
+	}
+	g := res.(*Grid)
+	columns := g.Columns()
+	if len(columns) != 2 || columns[0].QueryId() != 11 || columns[1].QueryId() != 12 {
+		t.Fatalf("Unexpected columns: %+v", columns)
+	}
+
+	wp := NewWorkPackage()
+	wp.SetField("lockVersion", 2)
+	wp.AddLink("updateImmediately", Link{Href: "/api/v3/work_packages/3", Method: "patch"})
+	moved, err := g.MoveWorkPackage(ts.client, wp, columns[1])
+	if err != nil {
+		t.Fatalf("Failed to move work package: %v", err)
+	}
+	if moved.LockVersion() != 3 {
+		t.Errorf("Expected updated work package.")
+	}
+}
+
+func TestWorkPackage_ExecuteCustomAction(t *testing.T) {
+	ts := newTestServer()
+	defer ts.Close()
+
+	ts.client.SetAPIKey(testAPIKey)
+
+	ts.addStatic("/api/v3/work_packages/3", `{"_type":"WorkPackage","id":3,"lockVersion":2,
+		"_links":{"self":{"href":"/api/v3/work_packages/3"}}}`, true)
+	ts.addStatic("/api/v3/custom_actions/1", `{"_type":"CustomAction","name":"Reset","description":"",
+		"_links":{"self":{"href":"/api/v3/custom_actions/1"},
+			"executeImmediately":{"href":"/api/v3/custom_actions/1/execute","method":"post"}}}`, true)
+	attempts := 0
+	ts.router.HandleFunc("/api/v3/custom_actions/1/execute", func(w http.ResponseWriter, req *http.Request) {
+		attempts++
+		var payload struct {
+			LockVersion int             `json:"lockVersion"`
+			Links       map[string]Link `json:"_links"`
+		}
+		json.NewDecoder(req.Body).Decode(&payload)
+		if payload.Links["workPackage"].Href != "/api/v3/work_packages/3" {
+			t.Errorf("Missing workPackage link: %+v", payload)
+		}
+		if payload.LockVersion != 2 {
+			halErrorHandler(w, http.StatusConflict, ErrorUpdateConflict,
+				"Your changes could not be saved, because the work package was changed.")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"_type":"WorkPackage","id":3,"lockVersion":3,"_links":{"self":{"href":"/api/v3/work_packages/3"}}}`)
+	})
+
+	wp := NewWorkPackage()
+	wp.SetField("lockVersion", 1)
+	wp.AddLink("self", Link{Href: "/api/v3/work_packages/3"})
+	wp.SetLinks("customActions", []Link{{Href: "/api/v3/custom_actions/1", Title: "Reset"}})
+
+	actions, err := wp.GetCustomActions(ts.client)
+	if err != nil || len(actions) != 1 || actions[0].Name() != "Reset" {
+		t.Fatalf("Failed to load custom actions: %v", err)
+	}
+	current, err := wp.ExecuteCustomAction(ts.client, actions[0])
+	if resErr, ok := err.(*Error); !ok || resErr.ErrorIdentifier() != ErrorUpdateConflict {
+		t.Fatalf("Expected update conflict, got: %v", err)
+	}
+	if current == nil || current.LockVersion() != 2 || attempts != 1 {
+		t.Fatalf("Expected reloaded work package without retry: %v, %d attempts", current, attempts)
+	}
+
+	// Execute again on the reloaded work package.
+	updated, err := current.ExecuteCustomAction(ts.client, actions[0])
+	if err != nil {
+		t.Fatalf("Failed to execute custom action: %v", err)
+	}
+	if updated.LockVersion() != 3 || attempts != 2 {
+		t.Errorf("Unexpected result: lockVersion %d, %d attempts", updated.LockVersion(), attempts)
+	}
+}
+
+func TestWorkPackage_CreateFileLinks(t *testing.T) {
+	ts := newTestServer()
+	defer ts.Close()
+
+	ts.client.SetAPIKey(testAPIKey)
+
+	ts.router.HandleFunc("/api/v3/work_packages/3/file_links", func(w http.ResponseWriter, req *http.Request) {
+		var body struct {
+			Embedded struct {
+				Elements []struct {
+					OriginData map[string]interface{} `json:"originData"`
+					Links      map[string]Link        `json:"_links"`
+				} `json:"elements"`
+			} `json:"_embedded"`
+		}
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil || len(body.Embedded.Elements) != 1 {
+			t.Errorf("Bad file links request: %v", err)
+			return
+		}
+		el := body.Embedded.Elements[0]
+		if el.Links["storage"].Href != "/api/v3/storages/1" || el.OriginData["name"] != "logo.png" {
+			t.Errorf("Unexpected file link: %+v", el)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"_type":"Collection","total":1,"count":1,"_embedded":{"elements":[
+			{"_type":"FileLink","id":1337,
+				"originData":{"id":"5503","name":"logo.png","mimeType":"image/png","size":16042,
+					"createdAt":"2021-12-19T09:42:10.170Z","lastModifiedAt":"2021-12-20T14:00:13.987Z",
+					"createdByName":"Luke Skywalker","lastModifiedByName":"Anakin Skywalker"},
+				"_links":{"self":{"href":"/api/v3/file_links/1337"},
+					"storage":{"href":"/api/v3/storages/1"},
+					"delete":{"href":"/api/v3/file_links/1337","method":"delete"},
+					"staticOriginOpen":{"href":"/api/v3/file_links/1337/open"}}}]}}`)
+	})
+
+	storage := NewStorage()
+	storage.AddLink("self", *NewLink("/api/v3/storages/1"))
+	fl := NewFileLink()
+	fl.SetStorage(storage)
+	fl.SetOriginData(&FileLinkOriginData{Id: "5503", Name: "logo.png", MimeType: "image/png", Size: 16042})
+
+	wp := NewWorkPackage()
+	wp.AddLink("fileLinks", Link{Href: "/api/v3/work_packages/3/file_links"})
+	col, err := wp.CreateFileLinks(ts.client, fl)
+	if err != nil {
+		t.Fatalf("Failed to create file links: %v", err)
+	}
+	created, ok := col.Items()[0].(*FileLink)
+	if !ok {
+		t.Fatalf("Expected FileLink.")
+	}
+	origin := created.OriginData()
+	if origin == nil || origin.Size != 16042 || origin.LastModifiedAt == nil ||
+		origin.LastModifiedAt.Day() != 20 || origin.LastModifiedByName != "Anakin Skywalker" {
+		t.Errorf("Unexpected origin data: %+v", origin)
+	}
+	if created.OriginOpenURL() != "/api/v3/file_links/1337/open" {
+		t.Errorf("Unexpected open URL: %s", created.OriginOpenURL())
+	}
+}
+
+func TestHalClient_RenderMarkdown(t *testing.T) {
+	ts := newTestServer()
+	defer ts.Close()
+
+	ts.router.HandleFunc("/api/v3/render/markdown", func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != "POST" {
+			t.Errorf("Expected POST, got %s", req.Method)
+		}
+		if ct := req.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+			t.Errorf("Unexpected content type: %s", ct)
+		}
+		if ctx := req.URL.Query().Get("context"); ctx != "/api/v3/work_packages/42" {
+			t.Errorf("Unexpected context: %s", ctx)
+		}
+		body, _ := ioutil.ReadAll(req.Body)
+		if string(body) != "Hello **world**" {
+			t.Errorf("Unexpected body: %q", body)
+		}
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, "<p>Hello <strong>world</strong></p>")
+	})
+
+	f, err := ts.client.RenderMarkdown("Hello **world**", NewLink("/api/v3/work_packages/42"))
+	if err != nil {
+		t.Fatalf("Failed to render markdown: %v", err)
+	}
+	if f.Format != "markdown" || f.Raw != "Hello **world**" ||
+		f.Html != "<p>Hello <strong>world</strong></p>" {
+		t.Errorf("Unexpected formattable: %+v", f)
+	}
+}
+
+func TestProject_CreateVersion(t *testing.T) {
+	ts := newTestServer()
+	defer ts.Close()
+
+	ts.router.HandleFunc("/api/v3/versions", func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != "POST" {
+			t.Errorf("Expected POST, got %s", req.Method)
+		}
+		var body struct {
+			Name  string          `json:"name"`
+			Links map[string]Link `json:"_links"`
+		}
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+			return
+		}
+		if body.Name != "Sprint 2" || body.Links["definingProject"].Href != "/api/v3/projects/3" {
+			t.Errorf("Unexpected request: %+v", body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"_type":"Version","id":12,"name":"Sprint 2",
+			"_links":{"self":{"href":"/api/v3/versions/12"},
+				"definingProject":{"href":"/api/v3/projects/3"}}}`)
+	})
+	ts.router.HandleFunc("/api/v3/work_packages", func(w http.ResponseWriter, req *http.Request) {
+		if f := req.URL.Query().Get("filters"); f != `[{"version":{"operator":"=","values":["12"]}}]` {
+			t.Errorf("Unexpected filters: %s", f)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"_type":"Collection","total":0,"count":0,"_embedded":{"elements":[]}}`)
+	})
+
+	project := NewProject()
+	project.AddLink("self", *NewLink("/api/v3/projects/3"))
+	v := NewVersion()
+	v.SetName("Sprint 2")
+	created, err := project.CreateVersion(ts.client, v)
+	if err != nil {
+		t.Fatalf("Failed to create version: %v", err)
+	}
+	if created.Id() != 12 {
+		t.Errorf("Unexpected version id: %d", created.Id())
+	}
+	if _, err := created.GetWorkPackages(ts.client); err != nil {
+		t.Errorf("Failed to list version work packages: %v", err)
+	}
+}
+
+func TestHalClient_RootRetry(t *testing.T) {
+	ts := newTestServer()
+	defer ts.Close()
+
+	rootRequests := 0
+	ts.router.HandleFunc("/api/v3", func(w http.ResponseWriter, req *http.Request) {
+		rootRequests++
+		if _, pass, ok := req.BasicAuth(); !ok || pass != testAPIKey {
+			halErrorHandler(w, http.StatusUnauthorized,
+				"urn:openproject-org:api:v3:errors:Unauthenticated", "Unauthenticated")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"_type":"Root","_links":{"self":{"href":"/api/v3"},
+			"notifications":{"href":"/api/v3/notifications"}}}`)
+	})
+
+	// Failures aren't cached.
+	if _, err := ts.client.Root(); err == nil {
+		t.Fatalf("Expected Root error.")
+	}
+	if _, err := ts.client.Supports(FeatureNotifications); err == nil {
+		t.Fatalf("Expected feature detection error.")
+	}
+	if rootRequests != 2 {
+		t.Errorf("Expected Root to be requested again after a failure, got %d requests", rootRequests)
+	}
+
+	ts.client.SetAPIKey(testAPIKey)
+	if _, err := ts.client.Root(); err != nil {
+		t.Fatalf("Failed to get Root: %v", err)
+	}
+	if supported, err := ts.client.Supports(FeatureNotifications); err != nil || !supported {
+		t.Errorf("Expected notifications to be supported: %v %v", supported, err)
+	}
+	if rootRequests != 3 {
+		t.Errorf("Expected a successful Root to be cached, got %d requests", rootRequests)
+	}
+
+	// Changing the user forgets the Root.
+	ts.client.SetAPIKey(testAPIKey)
+	if _, err := ts.client.Root(); err != nil || rootRequests != 4 {
+		t.Errorf("Expected Root to be requested again after SetAPIKey: %v, %d requests", err, rootRequests)
+	}
+
+	// Changing the API root while requests run must be safe.
+	done := make(chan bool)
+	go func() {
+		for i := 0; i < 10; i++ {
+			ts.client.SetAPIRoot("/api/v3")
+		}
+		done <- true
+	}()
+	for i := 0; i < 10; i++ {
+		ts.client.endpoint("/configuration", "configuration")
+	}
+	<-done
+}
+
+func TestHalClient_Queries(t *testing.T) {
+	ts := newTestServer()
+	defer ts.Close()
+	// API mounted below a different path.
+	ts.client.SetAPIRoot("/op/api/v3")
+
+	ts.router.HandleFunc("/op/api/v3/queries", func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != "POST" {
+			t.Errorf("Expected POST, got %s", req.Method)
+		}
+		body, _ := ioutil.ReadAll(req.Body)
+		for _, href := range []string{
+			`"/op/api/v3/queries/filters/status"`,
+			`"/op/api/v3/queries/operators/="`,
+			`"/op/api/v3/queries/columns/subject"`,
+		} {
+			if !strings.Contains(string(body), href) {
+				t.Errorf("Expected %s in request: %s", href, body)
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"_type":"Query","id":9,"name":"Open bugs","starred":false,
+			"_links":{"self":{"href":"/op/api/v3/queries/9"},
+				"star":{"href":"/op/api/v3/queries/9/star","method":"patch"}}}`)
+	})
+	ts.router.HandleFunc("/op/api/v3/queries/9/star", func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != "PATCH" {
+			t.Errorf("Expected PATCH, got %s", req.Method)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"_type":"Query","id":9,"starred":true,
+			"_links":{"self":{"href":"/op/api/v3/queries/9"},
+				"unstar":{"href":"/op/api/v3/queries/9/unstar","method":"patch"}}}`)
+	})
+	ts.router.HandleFunc("/op/api/v3/queries/9/unstar", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"_type":"Query","id":9,"starred":false,
+			"_links":{"self":{"href":"/op/api/v3/queries/9"}}}`)
+	})
+	ts.router.HandleFunc("/op/api/v3/queries/9", func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Query().Get("offset") != "1" || req.URL.Query().Get("pageSize") != "1" {
+			t.Errorf("Unexpected paging: %s", req.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"_type":"Query","id":9,"_embedded":{"results":{
+			"_type":"WorkPackageCollection","total":2,"count":1,"pageSize":1,"offset":1,
+			"_embedded":{"elements":[{"_type":"WorkPackage","id":1}]},
+			"_links":{"nextByOffset":{"href":"/op/api/v3/work_packages?offset=2&pageSize=1"}}}},
+			"_links":{"self":{"href":"/op/api/v3/queries/9"}}}`)
+	})
+	ts.router.HandleFunc("/op/api/v3/work_packages", func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Query().Get("offset") != "2" {
+			t.Errorf("Unexpected page: %s", req.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"_type":"WorkPackageCollection","total":2,"count":1,"pageSize":1,"offset":2,
+			"_embedded":{"elements":[{"_type":"WorkPackage","id":2}]}}`)
+	})
+
+	q := NewQuery()
+	q.SetName("Open bugs")
+	q.SetFilters(ts.client, NewFilters().Filter("status", "=", "1"))
+	q.SetColumns(ts.client, "id", "subject")
+	q, err := ts.client.CreateQuery(q)
+	if err != nil {
+		t.Fatalf("Failed to create query: %v", err)
+	}
+	if q.Id() != 9 || q.Starred() {
+		t.Errorf("Unexpected query: %d %v", q.Id(), q.Starred())
+	}
+	if q, err = q.Star(ts.client); err != nil || !q.Starred() {
+		t.Fatalf("Failed to star query: %v", err)
+	}
+	if q, err = q.Unstar(ts.client); err != nil || q.Starred() {
+		t.Fatalf("Failed to unstar query: %v", err)
+	}
+
+	results, err := ts.client.RunQuery(9, 1, 1)
+	if err != nil {
+		t.Fatalf("Failed to run query: %v", err)
+	}
+	if results.Total() != 2 || len(results.Items()) != 1 {
+		t.Errorf("Unexpected first page: %d %d", results.Total(), len(results.Items()))
+	}
+	next, err := results.NextPage(ts.client)
+	if err != nil {
+		t.Fatalf("Failed to get next page: %v", err)
+	}
+	if wp, ok := next.Items()[0].(*WorkPackage); !ok || wp.Id() != 2 {
+		t.Errorf("Unexpected second page: %v", next.Items())
+	}
+}
+
+func TestProject_GetNewsAndDocuments(t *testing.T) {
+	ts := newTestServer()
+	defer ts.Close()
+
+	projectFilter := `[{"project_id":{"operator":"=","values":["3"]}}]`
+	ts.router.HandleFunc("/api/v3/news", func(w http.ResponseWriter, req *http.Request) {
+		if f := req.URL.Query().Get("filters"); f != projectFilter {
+			t.Errorf("Unexpected news filters: %s", f)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"_type":"Collection","total":1,"count":1,"_embedded":{"elements":[
+			{"_type":"News","id":1,"title":"Release 1.0"}]}}`)
+	})
+	ts.router.HandleFunc("/api/v3/documents", func(w http.ResponseWriter, req *http.Request) {
+		if f := req.URL.Query().Get("filters"); f != projectFilter {
+			t.Errorf("Unexpected document filters: %s", f)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"_type":"Collection","total":1,"count":1,"_embedded":{"elements":[
+			{"_type":"Document","id":3,"title":"Specs",
+				"_links":{"attachments":{"href":"/api/v3/documents/3/attachments"}}}]}}`)
+	})
+	ts.addStatic("/api/v3/documents/3/attachments", `{"_type":"Collection","total":1,"count":1,
+		"_embedded":{"elements":[{"_type":"Attachment","id":8,"fileName":"specs.pdf"}]}}`, false)
+
+	project := NewProject()
+	project.SetField("id", 3)
+
+	news, err := project.GetNews(ts.client)
+	if err != nil {
+		t.Fatalf("Failed to get news: %v", err)
+	}
+	if n, ok := news.Items()[0].(*News); !ok || n.Title() != "Release 1.0" {
+		t.Errorf("Unexpected news: %v", news.Items())
+	}
+
+	docs, err := project.GetDocuments(ts.client)
+	if err != nil {
+		t.Fatalf("Failed to get documents: %v", err)
+	}
+	doc, ok := docs.Items()[0].(*Document)
+	if !ok {
+		t.Fatalf("Expected Document, got %T", docs.Items()[0])
+	}
+	atts := doc.GetAttachments(ts.client)
+	if atts == nil {
+		t.Fatalf("Failed to load document attachments.")
+	}
+	if att, ok := atts.Items()[0].(*Attachment); !ok || att.Id() != 8 {
+		t.Errorf("Unexpected attachments: %v", atts.Items())
+	}
+}
+
+func TestHalClient_CreateBoardCleanup(t *testing.T) {
+	ts := newTestServer()
+	defer ts.Close()
+
+	created := 0
+	var deleted []string
+	ts.router.HandleFunc("/api/v3/queries", func(w http.ResponseWriter, req *http.Request) {
+		created++
+		if created > 2 {
+			halErrorHandler(w, http.StatusUnprocessableEntity,
+				"urn:openproject-org:api:v3:errors:PropertyConstraintViolation", "Invalid")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"_type":"Query","id":%d,"_links":{"self":{"href":"/api/v3/queries/%d"}}}`,
+			created, created)
+	})
+	ts.router.HandleFunc("/api/v3/queries/", func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != "DELETE" {
+			t.Errorf("Expected DELETE, got %s", req.Method)
+		}
+		deleted = append(deleted, req.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	ts.router.HandleFunc("/api/v3/grids", func(w http.ResponseWriter, req *http.Request) {
+		halErrorHandler(w, http.StatusForbidden,
+			"urn:openproject-org:api:v3:errors:MissingPermission", "Forbidden")
+	})
+
+	project := NewProject()
+	project.SetField("identifier", "lectio")
+	project.AddLink("self", *NewLink("/api/v3/projects/3"))
+	statuses := []*Status{NewStatus(), NewStatus(), NewStatus()}
+	for i, st := range statuses {
+		st.SetField("id", i+1)
+	}
+
+	// Third query fails.
+	if _, err := ts.client.CreateBoard(project, "Board", statuses); err == nil {
+		t.Fatalf("Expected query error.")
+	}
+	if fmt.Sprint(deleted) != "[/api/v3/queries/1 /api/v3/queries/2]" {
+		t.Errorf("Unexpected deleted queries: %v", deleted)
+	}
+
+	// Grid creation fails.
+	created, deleted = 0, nil
+	if _, err := ts.client.CreateBoard(project, "Board", statuses[:2]); err == nil {
+		t.Fatalf("Expected grid error.")
+	}
+	if fmt.Sprint(deleted) != "[/api/v3/queries/1 /api/v3/queries/2]" {
+		t.Errorf("Unexpected deleted queries: %v", deleted)
+	}
+}
+
+func TestHalClient_CreateBoard(t *testing.T) {
+	ts := newTestServer()
+	defer ts.Close()
+
+	created := 0
+	ts.router.HandleFunc("/api/v3/queries", func(w http.ResponseWriter, req *http.Request) {
+		created++
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"_type":"Query","id":%d,"_links":{"self":{"href":"/api/v3/queries/%d"}}}`,
+			created+20, created+20)
+	})
+	ts.router.HandleFunc("/api/v3/grids", func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != "POST" {
+			t.Errorf("Expected POST, got %s", req.Method)
+		}
+		var grid struct {
+			Type        string `json:"_type"`
+			Name        string `json:"name"`
+			RowCount    int    `json:"rowCount"`
+			ColumnCount int    `json:"columnCount"`
+			Options     struct {
+				Type      string `json:"type"`
+				Attribute string `json:"attribute"`
+			} `json:"options"`
+			Widgets []struct {
+				Identifier  string `json:"identifier"`
+				StartColumn int    `json:"startColumn"`
+				EndColumn   int    `json:"endColumn"`
+				Options     struct {
+					QueryId string          `json:"queryId"`
+					Filters json.RawMessage `json:"filters"`
+				} `json:"options"`
+			} `json:"widgets"`
+			Links map[string]Link `json:"_links"`
+		}
+		if err := json.NewDecoder(req.Body).Decode(&grid); err != nil {
+			t.Errorf("Failed to decode grid: %v", err)
+			return
+		}
+		if grid.Type != "Grid" || grid.Name != "Status board" ||
+			grid.Links["scope"].Href != "/projects/lectio/boards" {
+			t.Errorf("Unexpected grid: %+v", grid)
+		}
+		if grid.RowCount != 1 || grid.ColumnCount != 2 {
+			t.Errorf("Unexpected grid size: %d x %d", grid.RowCount, grid.ColumnCount)
+		}
+		if grid.Options.Type != "action" || grid.Options.Attribute != "status" {
+			t.Errorf("Unexpected grid options: %+v", grid.Options)
+		}
+		if len(grid.Widgets) != 2 {
+			t.Errorf("Expected 2 widgets, got %d", len(grid.Widgets))
+			return
+		}
+		for i, w := range grid.Widgets {
+			if w.Identifier != "work_package_query" || w.StartColumn != i+1 || w.EndColumn != i+2 {
+				t.Errorf("Unexpected widget %d: %+v", i, w)
+			}
+			if w.Options.QueryId != fmt.Sprint(21+i) {
+				t.Errorf("Unexpected widget %d query: %s", i, w.Options.QueryId)
+			}
+			filters := fmt.Sprintf(`[{"status":{"operator":"=","values":["%d"]}}]`, i+1)
+			if string(w.Options.Filters) != filters {
+				t.Errorf("Unexpected widget %d filters: %s", i, w.Options.Filters)
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"_type":"Grid","id":5,"name":"Status board",
+			"_links":{"self":{"href":"/api/v3/grids/5"}}}`)
+	})
+
+	project := NewProject()
+	project.SetField("identifier", "lectio")
+	project.AddLink("self", *NewLink("/api/v3/projects/3"))
+	statuses := []*Status{NewStatus(), NewStatus()}
+	for i, st := range statuses {
+		st.SetField("id", i+1)
+	}
+
+	board, err := ts.client.CreateBoard(project, "Status board", statuses)
+	if err != nil {
+		t.Fatalf("Failed to create board: %v", err)
+	}
+	if board.Id() != 5 || created != 2 {
+		t.Errorf("Unexpected board: %d, %d queries", board.Id(), created)
+	}
+}
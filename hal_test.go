@@ -1,7 +1,11 @@
 package hal
 
 import (
+	"encoding/json"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestCollection_Unmarshal(t *testing.T) {
@@ -65,3 +69,675 @@ func TestError_Unmarshal(t *testing.T) {
 		t.Errorf("Hal resource isn't an Error object.")
 	}
 }
+
+func TestProject_Unmarshal(t *testing.T) {
+	s := `{"_type":"Project","id":4,"identifier":"lectio-web","name":"Lectio Web",
+		"active":true,"public":false,
+		"description":{"format":"markdown","raw":"The *web* frontend.","html":"<p>The <em>web</em> frontend.</p>"},
+		"statusExplanation":{"format":"markdown","raw":"Waiting on review.","html":"<p>Waiting on review.</p>"},
+		"_links":{
+			"self":{"href":"/api/v3/projects/4","title":"Lectio Web"},
+			"parent":{"href":"/api/v3/projects/3","title":"Lectio"},
+			"status":{"href":"/api/v3/project_statuses/at_risk","title":"At risk"}
+		}
+	}`
+	res, err := Unmarshal([]byte(s))
+	if err != nil {
+		t.Fatalf("Failed to parse Project %v.", err)
+	}
+	p, ok := res.(*Project)
+	if !ok {
+		t.Fatalf("Failed to cast Resource to Project.")
+	}
+	if p.Identifier() != "lectio-web" || !p.Active() || p.Public() {
+		t.Errorf("Unexpected project fields: %s %v %v", p.Identifier(), p.Active(), p.Public())
+	}
+	if d := p.Description(); d == nil || d.Raw != "The *web* frontend." {
+		t.Errorf("Unexpected description: %+v", d)
+	}
+	if p.Status() != ProjectStatusAtRisk {
+		t.Errorf("Unexpected status: %s", p.Status())
+	}
+	if e := p.StatusExplanation(); e == nil || e.Raw != "Waiting on review." {
+		t.Errorf("Unexpected status explanation: %+v", e)
+	}
+	if parent := p.GetParent(nil); parent != nil {
+		t.Errorf("Parent isn't embedded and can't be loaded without a client.")
+	}
+
+	// Move to the top level and clear the status.
+	p.SetParent(nil)
+	p.SetStatus("")
+	if p.Status() != "" {
+		t.Errorf("Expected no status: %s", p.Status())
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		t.Fatalf("Failed to encode: %v", err)
+	}
+	for _, expected := range []string{`"parent":{"href":null}`, `"status":{"href":null}`} {
+		if !strings.Contains(string(data), expected) {
+			t.Errorf("Expected %s in %s", expected, data)
+		}
+	}
+}
+
+func TestMembership_Unmarshal(t *testing.T) {
+	s := `{"_type":"Membership","id":11,
+		"_embedded":{
+			"principal":{"_type":"Group","id":8,"name":"Developers",
+				"_links":{"self":{"href":"/api/v3/groups/8"},
+					"members":[{"href":"/api/v3/users/4"},{"href":"/api/v3/users/5"}]}}
+		},
+		"_links":{
+			"self":{"href":"/api/v3/memberships/11"},
+			"project":{"href":"/api/v3/projects/3","title":"Lectio"},
+			"principal":{"href":"/api/v3/groups/8","title":"Developers"},
+			"roles":[
+				{"href":"/api/v3/roles/3","title":"Member"},
+				{"href":"/api/v3/roles/4","title":"Reader"}
+			]
+		}
+	}`
+	res, err := Unmarshal([]byte(s))
+	if err != nil {
+		t.Fatalf("Failed to parse Membership %v.", err)
+	}
+	m, ok := res.(*Membership)
+	if !ok {
+		t.Fatalf("Failed to cast Resource to Membership.")
+	}
+	principal := m.GetPrincipal(nil)
+	if g, ok := principal.(*Group); !ok || g.Name() != "Developers" || len(g.Members()) != 2 {
+		t.Errorf("Unexpected principal: %+v", principal)
+	}
+	if roles := m.GetLinks("roles"); len(roles) != 2 || roles[1].Title != "Reader" {
+		t.Errorf("Unexpected roles: %+v", roles)
+	}
+	if project := m.GetLinks("project"); len(project) != 1 {
+		t.Errorf("Single link should be returned as a list: %+v", project)
+	}
+
+	// Link arrays must survive a round-trip.
+	buf, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("Failed to marshal Membership: %v", err)
+	}
+	m2 := NewMembership()
+	if err := m2.UnmarshalHAL(buf); err != nil {
+		t.Fatalf("Failed to parse Membership %v.", err)
+	}
+	if roles := m2.GetLinks("roles"); len(roles) != 2 {
+		t.Errorf("Roles lost in round-trip: %s", buf)
+	}
+
+	var _ Principal = NewUser()
+	var _ Principal = NewPlaceholderUser()
+}
+
+func TestQuery_Filters(t *testing.T) {
+	s := `{"_type":"Query","id":9,"name":"Open bugs","starred":true,
+		"filters":[
+			{"_type":"StatusQueryFilter","name":"Status","_links":{
+				"filter":{"href":"/api/v3/queries/filters/status","title":"Status"},
+				"operator":{"href":"/api/v3/queries/operators/o","title":"open"},
+				"values":[]}},
+			{"_type":"TypeQueryFilter","name":"Type","_links":{
+				"filter":{"href":"/api/v3/queries/filters/type","title":"Type"},
+				"operator":{"href":"/api/v3/queries/operators/%3D","title":"is"},
+				"values":[{"href":"/api/v3/types/7","title":"Bug"}]}},
+			{"_type":"SubjectQueryFilter","name":"Subject","values":["crash"],"_links":{
+				"filter":{"href":"/api/v3/queries/filters/subject","title":"Subject"},
+				"operator":{"href":"/api/v3/queries/operators/~","title":"contains"}}}
+		],
+		"_embedded":{"results":{"_type":"WorkPackageCollection","total":0,"count":0,
+			"_embedded":{"elements":[]}}},
+		"_links":{
+			"self":{"href":"/api/v3/queries/9"},
+			"columns":[{"href":"/api/v3/queries/columns/id"},{"href":"/api/v3/queries/columns/subject"}],
+			"sortBy":[{"href":"/api/v3/queries/sort_bys/id-asc"}],
+			"groupBy":{"href":null}
+		}
+	}`
+	res, err := Unmarshal([]byte(s))
+	if err != nil {
+		t.Fatalf("Failed to parse Query %v.", err)
+	}
+	q := res.(*Query)
+	expected := `[{"status":{"operator":"o","values":[]}},{"type":{"operator":"=","values":["7"]}},{"subject":{"operator":"~","values":["crash"]}}]`
+	if f := q.Filters().String(); f != expected {
+		t.Errorf("Unexpected filters:\n%s\n%s", f, expected)
+	}
+	if cols := q.Columns(); len(cols) != 2 || cols[1] != "subject" {
+		t.Errorf("Unexpected columns: %v", cols)
+	}
+	if q.GroupBy() != "" || q.SortBy()[0] != "id-asc" {
+		t.Errorf("Unexpected groupBy/sortBy: %q %v", q.GroupBy(), q.SortBy())
+	}
+	if q.Results(nil) == nil {
+		t.Errorf("Expected embedded results.")
+	}
+
+	// Filters must survive a round-trip.
+	q2 := NewQuery()
+	q2.SetFilters(NewHalClient(""), q.Filters())
+	buf, _ := json.Marshal(q2)
+	q3 := NewQuery()
+	if err := q3.UnmarshalHAL(buf); err != nil {
+		t.Fatalf("Failed to parse Query %v.", err)
+	}
+	expected = `[{"status":{"operator":"o","values":[]}},{"type":{"operator":"=","values":["7"]}},{"subject":{"operator":"~","values":["crash"]}}]`
+	if f := q3.Filters().String(); f != expected {
+		t.Errorf("Unexpected filters after round-trip:\n%s\n%s", f, expected)
+	}
+}
+
+func TestSumTimeEntries(t *testing.T) {
+	s := `{"_type":"Collection","total":3,"count":3,"_embedded":{"elements":[
+		{"_type":"TimeEntry","id":1,"hours":"PT1H30M","spentOn":"2019-09-02",
+			"_links":{"user":{"href":"/api/v3/users/4","title":"Test User"},
+				"activity":{"href":"/api/v3/time_entries/activities/1","title":"Development"}}},
+		{"_type":"TimeEntry","id":2,"hours":"PT2H","spentOn":"2019-09-08",
+			"_links":{"user":{"href":"/api/v3/users/4","title":"Test User"},
+				"activity":{"href":"/api/v3/time_entries/activities/2","title":"Testing"}}},
+		{"_type":"TimeEntry","id":3,"hours":"PT45M","spentOn":"2019-09-09",
+			"_links":{"user":{"href":"/api/v3/users/5","title":"Test User"},
+				"activity":{"href":"/api/v3/time_entries/activities/1","title":"Development"}}},
+		{"_type":"TimeEntry","id":4,"hours":"PT15M","spentOn":"2019-09-09",
+			"_links":{"user":{"href":"/api/v3/users/4"},
+				"activity":{"href":"/api/v3/time_entries/activities/2"}}}
+	]}}`
+	res, err := Unmarshal([]byte(s))
+	if err != nil {
+		t.Fatalf("Failed to parse time entries %v.", err)
+	}
+	var entries []*TimeEntry
+	for _, item := range res.(*Collection).Items() {
+		entries = append(entries, item.(*TimeEntry))
+	}
+	if spentOn := entries[0].SpentOn(); spentOn == nil || spentOn.Day() != 2 {
+		t.Errorf("Failed to parse spentOn: %v", spentOn)
+	}
+
+	tests := map[string]map[string]time.Duration{
+		GroupByUser: {
+			"/api/v3/users/4": 3*time.Hour + 45*time.Minute,
+			"/api/v3/users/5": 45 * time.Minute,
+		},
+		GroupByActivity: {
+			"/api/v3/time_entries/activities/1": 2*time.Hour + 15*time.Minute,
+			"/api/v3/time_entries/activities/2": 2*time.Hour + 15*time.Minute,
+		},
+		GroupByWeek: {
+			"2019-W36": 3*time.Hour + 30*time.Minute,
+			"2019-W37": 1 * time.Hour,
+		},
+	}
+	for groupBy, expected := range tests {
+		sums := SumTimeEntries(entries, groupBy)
+		if fmt.Sprint(sums) != fmt.Sprint(expected) {
+			t.Errorf("Group by %s: got %v, expected %v", groupBy, sums, expected)
+		}
+	}
+
+	titles := TimeEntryGroupTitles(entries, GroupByActivity)
+	if titles["/api/v3/time_entries/activities/2"] != "Testing" ||
+		titles["/api/v3/time_entries/activities/1"] != "Development" {
+		t.Errorf("Unexpected titles: %v", titles)
+	}
+	if titles := TimeEntryGroupTitles(entries, GroupByWeek); titles["2019-W37"] != "2019-W37" {
+		t.Errorf("Unexpected week titles: %v", titles)
+	}
+}
+
+func TestForm_Schema(t *testing.T) {
+	res, err := Unmarshal([]byte(`{"_type":"Form","_embedded":{
+		"schema":{"_type":"Schema","_dependencies":[],
+			"spentOn":{"type":"Date","name":"Date","required":true,"hasDefault":false,"writable":true},
+			"id":{"type":"Integer","name":"ID","required":true,"hasDefault":false,"writable":false}},
+		"validationErrors":{"hours":{"_type":"Error","errorIdentifier":"urn:openproject-org:api:v3:errors:PropertyConstraintViolation","message":"Hours can't be blank."}}
+	}}`))
+	if err != nil {
+		t.Fatalf("Failed to parse Form: %v", err)
+	}
+	form := res.(*Form)
+	schema := form.Schema()
+	if fields := schema.Fields(); fmt.Sprint(fields) != "[id spentOn]" {
+		t.Errorf("Unexpected schema fields: %v", fields)
+	}
+	if f := schema.Field("spentOn"); f == nil || f.Label != "Date" || !f.Required || !f.Writable {
+		t.Errorf("Unexpected schema field: %+v", f)
+	}
+	errs := form.ValidationErrors()
+	if e, ok := errs["hours"]; !ok || e.Message() != "Hours can't be blank." {
+		t.Errorf("Unexpected validation errors: %v", errs)
+	}
+}
+
+func TestWorkCalendar(t *testing.T) {
+	res, err := Unmarshal([]byte(`{"_type":"Collection","total":8,"count":8,"_embedded":{"elements":[
+		{"_type":"WeekDay","day":1,"name":"Monday","working":true},
+		{"_type":"WeekDay","day":2,"name":"Tuesday","working":true},
+		{"_type":"WeekDay","day":3,"name":"Wednesday","working":true},
+		{"_type":"WeekDay","day":4,"name":"Thursday","working":true},
+		{"_type":"WeekDay","day":5,"name":"Friday","working":false},
+		{"_type":"WeekDay","day":6,"name":"Saturday","working":false},
+		{"_type":"WeekDay","day":7,"name":"Sunday","working":false},
+		{"_type":"NonWorkingDay","date":"2019-12-25","name":"Christmas"}
+	]}}`))
+	if err != nil {
+		t.Fatalf("Failed to parse days: %v", err)
+	}
+	var weekDays []*WeekDay
+	var nonWorking []*NonWorkingDay
+	for _, item := range res.(*Collection).Items() {
+		switch day := item.(type) {
+		case *WeekDay:
+			weekDays = append(weekDays, day)
+		case *NonWorkingDay:
+			nonWorking = append(nonWorking, day)
+		}
+	}
+	cal := NewWorkCalendar(weekDays, nonWorking)
+
+	date := func(s string) time.Time {
+		d, _ := time.Parse("2006-01-02", s)
+		return d
+	}
+	// Monday - Thursday are working days, Christmas isn't.
+	for s, expected := range map[string]bool{
+		"2019-12-23": true, "2019-12-25": false, "2019-12-26": true,
+		"2019-12-27": false, "2019-12-29": false,
+	} {
+		if cal.IsWorkingDay(date(s)) != expected {
+			t.Errorf("IsWorkingDay(%s) != %v", s, expected)
+		}
+	}
+	if d := cal.AddWorkingDays(date("2019-12-24"), 2); !d.Equal(date("2019-12-30")) {
+		t.Errorf("AddWorkingDays: %s", d.Format("2006-01-02"))
+	}
+	if d := cal.AddWorkingDays(date("2019-12-30"), -2); !d.Equal(date("2019-12-24")) {
+		t.Errorf("AddWorkingDays backwards: %s", d.Format("2006-01-02"))
+	}
+	if n := cal.WorkingDaysBetween(date("2019-12-23"), date("2019-12-31")); n != 5 {
+		t.Errorf("WorkingDaysBetween: %d", n)
+	}
+	if n := cal.WorkingDaysBetween(date("2019-12-31"), date("2019-12-23")); n != 0 {
+		t.Errorf("WorkingDaysBetween reversed: %d", n)
+	}
+	// The duration counts the start and due dates.
+	due := cal.DueDate(date("2019-12-23"), 5)
+	if !due.Equal(date("2019-12-31")) {
+		t.Errorf("DueDate: %s", due.Format("2006-01-02"))
+	}
+	if n := cal.WorkingDaysBetween(date("2019-12-23"), due); n != 5 {
+		t.Errorf("DueDate doesn't match duration: %d", n)
+	}
+}
+
+func TestDescribeAttributes(t *testing.T) {
+	res, err := Unmarshal([]byte(`{"_type":"Schema",
+		"subject":{"type":"String","name":"Subject","required":true,"hasDefault":false,"writable":true},
+		"customField3":{"type":"Integer","required":false,"hasDefault":false,"writable":true},
+		"status":{"type":"Status","name":"Status","required":true,"hasDefault":true,"writable":true,
+			"_links":{"allowedValues":{"href":"/api/v3/statuses"}}}
+	}`))
+	if err != nil {
+		t.Fatalf("Failed to parse Schema: %v", err)
+	}
+	schema := res.(*Schema)
+
+	res, err = Unmarshal([]byte(`{"_type":"Collection","total":3,"count":3,"_embedded":{"elements":[
+		{"_type":"HelpText","id":1,"attribute":"status","attributeCaption":"Status","scope":"WorkPackage",
+			"helpText":{"format":"markdown","raw":"Where the work is at.","html":"<p>Where the work is at.</p>"}},
+		{"_type":"HelpText","id":2,"attribute":"customField3","attributeCaption":"Story points","scope":"WorkPackage",
+			"helpText":{"format":"markdown","raw":"Estimated effort.","html":"<p>Estimated effort.</p>"}},
+		{"_type":"HelpText","id":3,"attribute":"status","attributeCaption":"Status","scope":"Project",
+			"helpText":{"format":"markdown","raw":"Project health.","html":"<p>Project health.</p>"}}
+	]}}`))
+	if err != nil {
+		t.Fatalf("Failed to parse help texts: %v", err)
+	}
+	var helpTexts []*HelpText
+	for _, item := range res.(*Collection).Items() {
+		helpTexts = append(helpTexts, item.(*HelpText))
+	}
+
+	descs := DescribeAttributes(schema, helpTexts, "WorkPackage")
+	if len(descs) != 3 {
+		t.Fatalf("Wrong number of attributes: %d", len(descs))
+	}
+	// Ordered by name: customField3, status, subject
+	if descs[0].Label != "Story points" || descs[0].HelpText.Raw != "Estimated effort." {
+		t.Errorf("Custom field label should come from the help text: %+v", descs[0])
+	}
+	if descs[1].HelpText.Raw != "Where the work is at." || descs[1].AllowedValuesLink == nil {
+		t.Errorf("Unexpected status attribute: %+v", descs[1])
+	}
+	if descs[2].Label != "Subject" || descs[2].HelpText != nil || !descs[2].Required {
+		t.Errorf("Unexpected subject attribute: %+v", descs[2])
+	}
+}
+
+func TestFormattable_PlainText(t *testing.T) {
+	f := NewFormattable("markdown", "",
+		`<h1>Release 1.2</h1><p>Fixed <strong>two</strong> bugs&nbsp;in the <a href="https://example.com/wp/3">parser</a>:</p>`+
+			`<ul><li>crash on &lt;empty&gt; input</li><li>slow <em>start</em></li></ul>`+
+			`<script>alert("x")</script><p>Thanks <a href="/users/4">@Test User</a><br>Bye</p>`)
+	expected := "Release 1.2\n\nFixed two bugs in the parser (https://example.com/wp/3):\n\n" +
+		"- crash on <empty> input\n- slow start\n\nThanks @Test User\nBye"
+	if text := f.PlainText(); text != expected {
+		t.Errorf("Unexpected plain text:\n%q\n%q", text, expected)
+	}
+
+	// No rendered HTML, strip the markdown.
+	f = NewFormattable("markdown", "## Notes\n\n* Use `snake_case` and **bold**\n"+
+		"* See [the docs](https://example.com/docs) or ![logo](logo.png)\n\n"+
+		"> _quoted_ text\n\n```\nfunc **main**() {}\n```", "")
+	expected = "Notes\n\n- Use snake_case and bold\n- See the docs (https://example.com/docs) or logo\n\n" +
+		"quoted text\n\nfunc **main**() {}"
+	if text := f.PlainText(); text != expected {
+		t.Errorf("Unexpected markdown text:\n%q\n%q", text, expected)
+	}
+
+	if text := NewFormattable("plain", "a *b*", "").PlainText(); text != "a *b*" {
+		t.Errorf("Plain text must be kept as-is: %q", text)
+	}
+
+	// Code spans are kept as-is and lone asterisks aren't emphasis.
+	tests := map[string]string{
+		"`[a](http://x)`":       "[a](http://x)",
+		"`**x**` and **y**":     "**x** and y",
+		"2 * 3 * 4":             "2 * 3 * 4",
+		"a ** b ** c and _ d _": "a ** b ** c and _ d _",
+		"*one* and ~~two~~":     "one and two",
+	}
+	for md, expected := range tests {
+		if text := MarkdownToText(md); text != expected {
+			t.Errorf("MarkdownToText(%q): got %q, expected %q", md, text, expected)
+		}
+	}
+}
+
+func TestFormattable_SanitizedHTML(t *testing.T) {
+	f := NewFormattable("markdown", "",
+		`<p class="x" onclick="steal()">Hi <b>there<i> you</p>`+
+			`<a href="javascript:alert(1)" title="t">bad</a> <a href="https://example.com">good</a>`+
+			`<img src="/attachments/1" alt="pic" onerror="x()"><iframe src="https://evil">hidden</iframe>`+
+			`<macro class="toc">toc</macro><script>alert(1)</script>`)
+	expected := `<p>Hi <b>there<i> you</i></b></p>` +
+		`<a title="t">bad</a> <a href="https://example.com">good</a>` +
+		`<img src="/attachments/1" alt="pic">toc`
+	if out := f.SanitizedHTML(nil); out != expected {
+		t.Errorf("Unexpected sanitized HTML:\n%s\n%s", out, expected)
+	}
+
+	policy := &SanitizePolicy{Elements: map[string][]string{"b": nil}}
+	if out := f.SanitizedHTML(policy); out != "Hi <b>there youbad goodtoc</b>" {
+		t.Errorf("Unexpected HTML with custom policy: %s", out)
+	}
+
+	f = NewFormattable("plain", "a < b\nc\n\nd", "")
+	if out := f.SanitizedHTML(nil); out != "<p>a &lt; b<br>c</p><p>d</p>" {
+		t.Errorf("Unexpected HTML from plain text: %s", out)
+	}
+}
+
+func TestFormattable_JSON(t *testing.T) {
+	res, err := Unmarshal([]byte(`{"_type":"WorkPackage","id":1,
+	"description":{"format":"markdown","raw":"Some *text*","html":"<p>Some <em>text</em></p>"},
+	"_links":{"self":{"href":"/api/v3/work_packages/1"}}}`))
+	if err != nil {
+		t.Fatalf("Failed to decode: %v", err)
+	}
+	wp := res.(*WorkPackage)
+	if d := wp.Description(); d == nil || d.Format != FormatMarkdown || d.Raw != "Some *text*" {
+		t.Fatalf("Unexpected description: %+v", d)
+	}
+
+	desc := &Formattable{Format: FormatCustom, Raw: "new"}
+	wp.SetDescription(desc)
+	if d := wp.Description(); d == nil || d.Raw != "new" {
+		t.Fatalf("Unexpected description: %+v", d)
+	}
+	// The resource keeps its own copy.
+	desc.Raw = "changed"
+	wp.Description().Raw = "changed"
+	if d := wp.Description(); d.Raw != "new" {
+		t.Fatalf("Description changed through a reference: %+v", d)
+	}
+	data, err := json.Marshal(wp)
+	if err != nil {
+		t.Fatalf("Failed to encode: %v", err)
+	}
+	res, err = Unmarshal(data)
+	if err != nil {
+		t.Fatalf("Failed to decode: %v", err)
+	}
+	if d := res.(*WorkPackage).Description(); d == nil || d.Format != FormatCustom ||
+		d.Raw != "new" || d.Html != "" {
+		t.Errorf("Unexpected description after round-trip: %+v", d)
+	}
+
+	var f Formattable
+	if err := json.Unmarshal([]byte(`{"format":"plain","raw":"a"}`), &f); err != nil || f.Format != FormatPlain {
+		t.Errorf("Unexpected formattable: %+v %v", f, err)
+	}
+	if data, _ := json.Marshal(Formattable{Raw: "x"}); string(data) != `{"raw":"x"}` {
+		t.Errorf("Unexpected encoding: %s", data)
+	}
+}
+
+func TestResourceObject_Curies(t *testing.T) {
+	res, err := Unmarshal([]byte(`{"_type":"WorkPackage","id":1,"_links":{
+	"curies":[{"name":"op","href":"https://docs.example.com/rels/{rel}","templated":true}],
+	"self":{"href":"/api/v3/work_packages/1"},
+	"op:watchers":{"href":"/api/v3/work_packages/1/watchers"},
+	"https://docs.example.com/rels/relations":{"href":"/api/v3/work_packages/1/relations"}}}`))
+	if err != nil {
+		t.Fatalf("Failed to decode: %v", err)
+	}
+	wp := res.(*WorkPackage)
+	if l := wp.GetLink("https://docs.example.com/rels/watchers"); l == nil || l.Href != "/api/v3/work_packages/1/watchers" {
+		t.Errorf("Expanded rel not resolved: %v", l)
+	}
+	if l := wp.GetLink("op:relations"); l == nil || l.Href != "/api/v3/work_packages/1/relations" {
+		t.Errorf("Compact rel not resolved: %v", l)
+	}
+	if l := wp.GetLink("self"); l == nil {
+		t.Errorf("Plain rel not found")
+	}
+	if doc := wp.LinkDocumentation("op:watchers"); doc != "https://docs.example.com/rels/watchers" {
+		t.Errorf("Unexpected documentation URL: %s", doc)
+	}
+	if doc := wp.LinkDocumentation("self"); doc != "" {
+		t.Errorf("Unexpected documentation URL: %s", doc)
+	}
+
+	wp.AddCurie("ex", "https://example.com/{rel}.html")
+	data, err := json.Marshal(wp)
+	if err != nil {
+		t.Fatalf("Failed to encode: %v", err)
+	}
+	res, err = Unmarshal(data)
+	if err != nil {
+		t.Fatalf("Failed to decode: %v", err)
+	}
+	wp = res.(*WorkPackage)
+	if curies := wp.GetLinks("curies"); len(curies) != 2 {
+		t.Errorf("Expected 2 curies, got: %v", curies)
+	}
+	if doc := wp.LinkDocumentation("ex:foo"); doc != "https://example.com/foo.html" {
+		t.Errorf("Unexpected documentation URL: %s", doc)
+	}
+	if l := wp.GetLink("https://docs.example.com/rels/watchers"); l == nil {
+		t.Errorf("Expanded rel not resolved after round-trip")
+	}
+
+	// Writing the expanded rel replaces the compact one.
+	wp.AddLink("https://docs.example.com/rels/watchers", *NewLink("/api/v3/watchers"))
+	if _, ok := wp.Links["op:watchers"]; ok {
+		t.Errorf("Link stored under both compact and expanded rel: %v", wp.Links)
+	}
+	if l := wp.GetLink("op:watchers"); l == nil || l.Href != "/api/v3/watchers" {
+		t.Errorf("Unexpected link: %v", l)
+	}
+
+	// A single curie link replaces the curies.
+	wp.AddLink("curies", Link{Name: "doc", Href: "https://example.org/{rel}", Templated: true})
+	if curies := wp.GetLinks("curies"); len(curies) != 1 {
+		t.Errorf("Expected 1 curie, got: %v", curies)
+	}
+	if doc := wp.LinkDocumentation("doc:foo"); doc != "https://example.org/foo" {
+		t.Errorf("Curies not refreshed: %s", doc)
+	}
+	if doc := wp.LinkDocumentation("op:watchers"); doc != "" {
+		t.Errorf("Stale curie: %s", doc)
+	}
+}
+
+func TestVersion_Unmarshal(t *testing.T) {
+	res, err := Unmarshal([]byte(`{"_type":"Version","id":11,"name":"Sprint 1",
+	"description":{"format":"plain","raw":"First sprint","html":"<p>First sprint</p>"},
+	"startDate":"2019-09-02","endDate":null,"status":"open","sharing":"system",
+	"_links":{
+		"self":{"href":"/api/v3/versions/11","title":"Sprint 1"},
+		"definingProject":{"href":"/api/v3/projects/3","title":"Lectio"},
+		"availableInProjects":{"href":"/api/v3/versions/11/projects"}}}`))
+	if err != nil {
+		t.Fatalf("Failed to decode Version: %v", err)
+	}
+	v, ok := res.(*Version)
+	if !ok {
+		t.Fatalf("Expected Version, got %T", res)
+	}
+	if v.Id() != 11 || v.Name() != "Sprint 1" || v.Status() != VersionStatusOpen ||
+		v.Sharing() != VersionSharingSystem {
+		t.Errorf("Unexpected version: %d %s %s %s", v.Id(), v.Name(), v.Status(), v.Sharing())
+	}
+	if d := v.Description(); d == nil || d.Raw != "First sprint" {
+		t.Errorf("Unexpected description: %+v", d)
+	}
+	start := v.StartDate()
+	if start == nil || start.Year() != 2019 || start.Month() != time.September || start.Day() != 2 {
+		t.Errorf("Unexpected start date: %v", start)
+	}
+	if end := v.EndDate(); end != nil {
+		t.Errorf("Expected no end date, got: %v", end)
+	}
+
+	v.SetEndDate(time.Date(2019, time.September, 13, 0, 0, 0, 0, time.UTC))
+	if s := v.GetString("endDate"); s != "2019-09-13" {
+		t.Errorf("Unexpected end date field: %s", s)
+	}
+
+	wp := NewWorkPackage()
+	wp.SetVersion(v)
+	if l := wp.GetLink("version"); l == nil || l.Href != "/api/v3/versions/11" {
+		t.Errorf("Unexpected version link: %v", l)
+	}
+
+	// A nil version clears the link.
+	wp.SetVersion(nil)
+	data, err := json.Marshal(wp)
+	if err != nil {
+		t.Fatalf("Failed to encode: %v", err)
+	}
+	if expected := `{"_links":{"version":{"href":null}},"_type":"WorkPackage"}`; string(data) != expected {
+		t.Errorf("Unexpected encoding:\n%s\n%s", data, expected)
+	}
+}
+
+func TestCategory_Unmarshal(t *testing.T) {
+	res, err := Unmarshal([]byte(`{"_type":"Category","id":10,"name":"Bugs",
+	"_links":{
+		"self":{"href":"/api/v3/categories/10","title":"Bugs"},
+		"project":{"href":"/api/v3/projects/3","title":"Lectio"},
+		"defaultAssignee":{"href":"/api/v3/users/5","title":"Test User"}},
+	"_embedded":{
+		"defaultAssignee":{"_type":"User","id":5,"name":"Test User",
+			"_links":{"self":{"href":"/api/v3/users/5"}}}}}`))
+	if err != nil {
+		t.Fatalf("Failed to decode Category: %v", err)
+	}
+	cat, ok := res.(*Category)
+	if !ok {
+		t.Fatalf("Expected Category, got %T", res)
+	}
+	if cat.Id() != 10 || cat.Name() != "Bugs" {
+		t.Errorf("Unexpected category: %d %s", cat.Id(), cat.Name())
+	}
+	if u := cat.GetDefaultAssignee(nil); u == nil || u.Id() != 5 {
+		t.Errorf("Unexpected default assignee: %v", u)
+	}
+
+	wp := NewWorkPackage()
+	wp.SetCategory(cat)
+	if l := wp.GetLink("category"); l == nil || l.Href != "/api/v3/categories/10" {
+		t.Errorf("Unexpected category link: %v", l)
+	}
+	wp.SetCategory(nil)
+	if data, _ := json.Marshal(wp); !strings.Contains(string(data), `"category":{"href":null}`) {
+		t.Errorf("Expected cleared category: %s", data)
+	}
+	if NewCategory().GetDefaultAssignee(nil) != nil {
+		t.Errorf("Expected no default assignee.")
+	}
+}
+
+func TestNewsWikiPageDocument_Unmarshal(t *testing.T) {
+	res, err := Unmarshal([]byte(`{"_type":"News","id":1,"title":"Release 1.0",
+	"summary":"Out now","description":{"format":"markdown","raw":"**Out** now"},
+	"_links":{"self":{"href":"/api/v3/news/1"},"project":{"href":"/api/v3/projects/3"}},
+	"_embedded":{"author":{"_type":"User","id":4,"name":"Test User"}}}`))
+	if err != nil {
+		t.Fatalf("Failed to decode News: %v", err)
+	}
+	news, ok := res.(*News)
+	if !ok {
+		t.Fatalf("Expected News, got %T", res)
+	}
+	if news.Id() != 1 || news.Title() != "Release 1.0" || news.Summary() != "Out now" ||
+		news.Description().Raw != "**Out** now" {
+		t.Errorf("Unexpected news: %d %s %s", news.Id(), news.Title(), news.Summary())
+	}
+	if u := news.GetAuthor(nil); u == nil || u.Id() != 4 {
+		t.Errorf("Unexpected author: %v", u)
+	}
+
+	res, err = Unmarshal([]byte(`{"_type":"WikiPage","id":2,"title":"Wiki",
+	"_links":{"self":{"href":"/api/v3/wiki_pages/2"},
+		"addAttachment":{"href":"/api/v3/wiki_pages/2/attachments","method":"post"}},
+	"_embedded":{"attachments":{"_type":"Collection","total":1,"count":1,
+		"_embedded":{"elements":[{"_type":"Attachment","id":7,"fileName":"a.txt"}]}}}}`))
+	if err != nil {
+		t.Fatalf("Failed to decode WikiPage: %v", err)
+	}
+	page, ok := res.(*WikiPage)
+	if !ok {
+		t.Fatalf("Expected WikiPage, got %T", res)
+	}
+	if page.Id() != 2 || page.Title() != "Wiki" {
+		t.Errorf("Unexpected wiki page: %d %s", page.Id(), page.Title())
+	}
+	if col := page.GetAttachments(nil); col == nil || col.Total() != 1 {
+		t.Errorf("Expected embedded attachments: %v", col)
+	}
+
+	res, err = Unmarshal([]byte(`{"_type":"Document","id":3,"title":"Specs",
+	"description":{"format":"markdown","raw":"The specs"},
+	"_links":{"self":{"href":"/api/v3/documents/3"}}}`))
+	if err != nil {
+		t.Fatalf("Failed to decode Document: %v", err)
+	}
+	doc, ok := res.(*Document)
+	if !ok {
+		t.Fatalf("Expected Document, got %T", res)
+	}
+	if doc.Id() != 3 || doc.Title() != "Specs" || doc.Description().Raw != "The specs" {
+		t.Errorf("Unexpected document: %d %s", doc.Id(), doc.Title())
+	}
+	if doc.GetAttachments(nil) != nil {
+		t.Errorf("Expected no attachments.")
+	}
+}
@@ -0,0 +1,118 @@
+package hal
+
+import (
+	"fmt"
+)
+
+//
+// HelpText
+//
+// Explanation of a resource attribute, shown next to the attribute in forms.
+//
+
+type HelpText struct {
+	ResourceObject
+}
+
+func NewHelpText() *HelpText {
+	return &HelpText{
+		ResourceObject{
+			Type: "HelpText",
+		},
+	}
+}
+
+func (res *HelpText) Id() int {
+	return res.GetInt("id")
+}
+
+// Attribute name as used in schemas, e.g. "status" or "customField3".
+func (res *HelpText) Attribute() string {
+	return res.GetString("attribute")
+}
+
+func (res *HelpText) AttributeCaption() string {
+	return res.GetString("attributeCaption")
+}
+
+// Resource type the attribute belongs to, e.g. "WorkPackage" or "Project".
+func (res *HelpText) Scope() string {
+	return res.GetString("scope")
+}
+
+func (res *HelpText) HelpText() *Formattable {
+	return res.GetFormattable("helpText")
+}
+
+func (c *HalClient) GetHelpTexts() ([]*HelpText, error) {
+	col, err := c.GetCollection(c.endpoint("/help_texts", "helpTexts"))
+	if err != nil {
+		return nil, err
+	}
+	items, err := col.AllItems(c)
+	if err != nil {
+		return nil, err
+	}
+	helpTexts := make([]*HelpText, 0, len(items))
+	for _, item := range items {
+		ht, ok := item.(*HelpText)
+		if !ok {
+			return nil, fmt.Errorf("Unknown resource type: %s", item.ResourceType())
+		}
+		helpTexts = append(helpTexts, ht)
+	}
+	return helpTexts, nil
+}
+
+//
+// AttributeDescriptor
+//
+// Everything needed to render a form field for an attribute.
+//
+
+type AttributeDescriptor struct {
+	SchemaField
+
+	HelpText *Formattable
+}
+
+// Describe the attributes of `schema`, ordered by name.  Only help texts for
+// `scope` (e.g. "WorkPackage") are used, an empty scope uses all help texts.
+func DescribeAttributes(schema *Schema, helpTexts []*HelpText, scope string) []*AttributeDescriptor {
+	byAttribute := make(map[string]*HelpText)
+	for _, ht := range helpTexts {
+		if scope == "" || ht.Scope() == scope {
+			byAttribute[ht.Attribute()] = ht
+		}
+	}
+
+	names := schema.Fields()
+	descriptors := make([]*AttributeDescriptor, 0, len(names))
+	for _, name := range names {
+		field := schema.Field(name)
+		if field == nil {
+			continue
+		}
+		desc := &AttributeDescriptor{
+			SchemaField: *field,
+		}
+		if ht, ok := byAttribute[name]; ok {
+			desc.HelpText = ht.HelpText()
+			if desc.Label == "" {
+				desc.Label = ht.AttributeCaption()
+			}
+		}
+		if desc.Label == "" {
+			desc.Label = name
+		}
+		descriptors = append(descriptors, desc)
+	}
+	return descriptors
+}
+
+// Register Factories
+func init() {
+	resourceTypes["HelpText"] = func() Resource {
+		return NewHelpText()
+	}
+}
@@ -0,0 +1,194 @@
+package hal
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+//
+// Role
+//
+
+type Role struct {
+	ResourceObject
+}
+
+func NewRole() *Role {
+	return &Role{
+		ResourceObject{
+			Type: "Role",
+		},
+	}
+}
+
+func (res *Role) Id() int {
+	return res.GetInt("id")
+}
+
+func (res *Role) Name() string {
+	return res.GetString("name")
+}
+
+//
+// Membership
+//
+
+type Membership struct {
+	ResourceObject
+}
+
+func NewMembership() *Membership {
+	return &Membership{
+		ResourceObject{
+			Type: "Membership",
+		},
+	}
+}
+
+func (res *Membership) Id() int {
+	return res.GetInt("id")
+}
+
+func (res *Membership) GetProject(c *HalClient) *Project {
+	// Get embedded project or load from a link
+	val := res.GetEmbeddedResource("project", c)
+	if p, ok := val.(*Project); ok {
+		return p
+	}
+	return nil
+}
+
+func (res *Membership) GetPrincipal(c *HalClient) Principal {
+	// Get embedded principal or load from a link
+	val := res.GetEmbeddedResource("principal", c)
+	if p, ok := val.(Principal); ok {
+		return p
+	}
+	return nil
+}
+
+func (res *Membership) GetRoles(c *HalClient) ([]*Role, error) {
+	// Get embedded roles or load each from a link
+	var items []Resource
+	if embedded := res.GetEmbeddedResourceList("roles"); embedded != nil {
+		items = embedded
+	} else {
+		for _, link := range res.GetLinks("roles") {
+			link := link
+			linkRes, err := c.LinkGet(&link)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, linkRes)
+		}
+	}
+	roles := make([]*Role, 0, len(items))
+	for _, item := range items {
+		role, ok := item.(*Role)
+		if !ok {
+			return nil, fmt.Errorf("Unknown resource type: %s", item.ResourceType())
+		}
+		roles = append(roles, role)
+	}
+	return roles, nil
+}
+
+func roleLinks(roles []*Role) []Link {
+	links := make([]Link, 0, len(roles))
+	for _, role := range roles {
+		if l := role.GetLink("self"); l != nil {
+			links = append(links, *l)
+		}
+	}
+	return links
+}
+
+// Replace the roles of this membership.
+func (res *Membership) SetRoles(c *HalClient, roles ...*Role) (*Membership, error) {
+	link := res.GetLink("updateImmediately")
+	if link == nil {
+		return nil, errors.New("No 'updateImmediately' Link")
+	}
+	patch := NewMembership()
+	patch.SetLinks("roles", roleLinks(roles))
+	newRes, err := c.Patch(link.Href, patch)
+	if err != nil {
+		return nil, err
+	}
+	if m, ok := newRes.(*Membership); ok {
+		return m, nil
+	}
+	return nil, fmt.Errorf("Unknown resource type: %s", newRes.ResourceType())
+}
+
+//
+// Project members
+//
+
+func (res *Project) GetMembers(c *HalClient) (*Collection, error) {
+	filters := NewFilters().Filter("project", "=", strconv.Itoa(res.Id()))
+	return c.GetFilteredCollection(c.endpoint("/memberships", "memberships"), filters)
+}
+
+// Get the membership of `principal` in this project.
+func (res *Project) GetMembership(c *HalClient, principal Principal) (*Membership, error) {
+	filters := NewFilters().
+		Filter("project", "=", strconv.Itoa(res.Id())).
+		Filter("principal", "=", strconv.Itoa(principal.Id()))
+	col, err := c.GetFilteredCollection(c.endpoint("/memberships", "memberships"), filters)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range col.Items() {
+		if m, ok := item.(*Membership); ok {
+			return m, nil
+		}
+	}
+	return nil, fmt.Errorf("'%s' isn't a member of project '%s'", principal.Name(), res.Name())
+}
+
+func (res *Project) AddMember(c *HalClient, principal Principal, roles ...*Role) (*Membership, error) {
+	m := NewMembership()
+	if l := res.GetLink("self"); l != nil {
+		m.AddLink("project", *l)
+	}
+	if l := principal.GetLink("self"); l != nil {
+		m.AddLink("principal", *l)
+	}
+	m.SetLinks("roles", roleLinks(roles))
+	newRes, err := c.Post(c.endpoint("/memberships", "memberships"), m)
+	if err != nil {
+		return nil, err
+	}
+	if m, ok := newRes.(*Membership); ok {
+		return m, nil
+	}
+	return nil, fmt.Errorf("Unknown resource type: %s", newRes.ResourceType())
+}
+
+func (res *Project) RemoveMember(c *HalClient, principal Principal) error {
+	m, err := res.GetMembership(c, principal)
+	if err != nil {
+		return err
+	}
+	return m.Delete(c)
+}
+
+//
+// Roles API
+//
+
+func (c *HalClient) GetRoles() (*Collection, error) {
+	return c.GetCollection(c.endpoint("/roles", "roles"))
+}
+
+// Register Factories
+func init() {
+	resourceTypes["Role"] = func() Resource {
+		return NewRole()
+	}
+	resourceTypes["Membership"] = func() Resource {
+		return NewMembership()
+	}
+}
@@ -0,0 +1,70 @@
+package hal
+
+import (
+	"strconv"
+)
+
+//
+// News
+//
+
+type News struct {
+	ResourceObject
+}
+
+func NewNews() *News {
+	return &News{
+		ResourceObject{
+			Type: "News",
+		},
+	}
+}
+
+func (res *News) Id() int {
+	return res.GetInt("id")
+}
+
+func (res *News) Title() string {
+	return res.GetString("title")
+}
+
+func (res *News) Summary() string {
+	return res.GetString("summary")
+}
+
+func (res *News) Description() *Formattable {
+	return res.GetFormattable("description")
+}
+
+func (res *News) GetAuthor(c *HalClient) *User {
+	// Get embedded author or load from a link
+	val := res.GetEmbeddedResource("author", c)
+	if u, ok := val.(*User); ok {
+		return u
+	}
+	return nil
+}
+
+func (res *News) GetProject(c *HalClient) *Project {
+	// Get embedded project or load from a link
+	val := res.GetEmbeddedResource("project", c)
+	if p, ok := val.(*Project); ok {
+		return p
+	}
+	return nil
+}
+
+func (c *HalClient) GetNews(filters *Filters) (*Collection, error) {
+	return c.GetFilteredCollection(c.endpoint("/news", "news"), filters)
+}
+
+func (res *Project) GetNews(c *HalClient) (*Collection, error) {
+	return c.GetNews(NewFilters().Filter("project_id", "=", strconv.Itoa(res.Id())))
+}
+
+// Register Factories
+func init() {
+	resourceTypes["News"] = func() Resource {
+		return NewNews()
+	}
+}
@@ -0,0 +1,119 @@
+package hal
+
+import (
+	"net/url"
+)
+
+//
+// Notification
+//
+// In-app notifications (IAN) about changes to work packages and other
+// resources.
+//
+
+const (
+	NotificationReasonMentioned   = "mentioned"
+	NotificationReasonAssigned    = "assigned"
+	NotificationReasonResponsible = "responsible"
+	NotificationReasonWatched     = "watched"
+	NotificationReasonCommented   = "commented"
+	NotificationReasonCreated     = "created"
+	NotificationReasonProcessed   = "processed"
+	NotificationReasonPrioritized = "prioritized"
+	NotificationReasonScheduled   = "scheduled"
+	NotificationReasonDateAlert   = "dateAlert"
+)
+
+type Notification struct {
+	ResourceObject
+}
+
+func NewNotification() *Notification {
+	return &Notification{
+		ResourceObject{
+			Type: "Notification",
+		},
+	}
+}
+
+func (res *Notification) Id() int {
+	return res.GetInt("id")
+}
+
+func (res *Notification) Reason() string {
+	return res.GetString("reason")
+}
+
+func (res *Notification) IsRead() bool {
+	return res.GetBool("readIAN")
+}
+
+func (res *Notification) GetActor(c *HalClient) *User {
+	// Get embedded actor or load from a link
+	val := res.GetEmbeddedResource("actor", c)
+	if u, ok := val.(*User); ok {
+		return u
+	}
+	return nil
+}
+
+func (res *Notification) GetProject(c *HalClient) *Project {
+	// Get embedded project or load from a link
+	val := res.GetEmbeddedResource("project", c)
+	if p, ok := val.(*Project); ok {
+		return p
+	}
+	return nil
+}
+
+// Link to the resource the notification is about (e.g. a work package).
+func (res *Notification) ResourceLink() *Link {
+	return res.GetLink("resource")
+}
+
+func (res *Notification) GetResource(c *HalClient) Resource {
+	return res.GetEmbeddedResource("resource", c)
+}
+
+func (res *Notification) MarkRead(c *HalClient) error {
+	if err := c.linkAction(res.GetLink("readIAN")); err != nil {
+		return err
+	}
+	res.SetField("readIAN", true)
+	return nil
+}
+
+func (res *Notification) MarkUnread(c *HalClient) error {
+	if err := c.linkAction(res.GetLink("unreadIAN")); err != nil {
+		return err
+	}
+	res.SetField("readIAN", false)
+	return nil
+}
+
+//
+// Notifications API
+//
+
+// List notifications, filtered by e.g. `readIAN`, `reason` or `project`:
+//
+//	NewFilters().Filter("readIAN", "=", "f").Filter("reason", "=", "mentioned")
+func (c *HalClient) GetNotifications(filters *Filters) (*Collection, error) {
+	return c.GetFilteredCollection(c.endpoint("/notifications", "notifications"), filters)
+}
+
+// Mark all notifications matching `filters` (all if nil) as read.
+func (c *HalClient) MarkAllNotificationsRead(filters *Filters) error {
+	path := c.endpoint("/notifications", "notifications") + "/read_ian"
+	if f := filters.String(); f != "" {
+		path += "?filters=" + url.QueryEscape(f)
+	}
+	return c.linkAction(&Link{Href: path, Method: "post"})
+}
+
+// Register Factories
+func init() {
+	resourceTypes["Notification"] = func() Resource {
+		return NewNotification()
+	}
+}
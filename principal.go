@@ -0,0 +1,76 @@
+package hal
+
+//
+// Principal
+//
+// Users, groups and placeholder users can be members of projects.
+//
+
+type Principal interface {
+	Resource
+	Id() int
+	Name() string
+}
+
+//
+// Group
+//
+
+type Group struct {
+	ResourceObject
+}
+
+func NewGroup() *Group {
+	return &Group{
+		ResourceObject{
+			Type: "Group",
+		},
+	}
+}
+
+func (res *Group) Id() int {
+	return res.GetInt("id")
+}
+
+func (res *Group) Name() string {
+	return res.GetString("name")
+}
+
+// Links to the users in this group.
+func (res *Group) Members() []Link {
+	return res.GetLinks("members")
+}
+
+//
+// PlaceholderUser
+//
+
+type PlaceholderUser struct {
+	ResourceObject
+}
+
+func NewPlaceholderUser() *PlaceholderUser {
+	return &PlaceholderUser{
+		ResourceObject{
+			Type: "PlaceholderUser",
+		},
+	}
+}
+
+func (res *PlaceholderUser) Id() int {
+	return res.GetInt("id")
+}
+
+func (res *PlaceholderUser) Name() string {
+	return res.GetString("name")
+}
+
+// Register Factories
+func init() {
+	resourceTypes["Group"] = func() Resource {
+		return NewGroup()
+	}
+	resourceTypes["PlaceholderUser"] = func() Resource {
+		return NewPlaceholderUser()
+	}
+}
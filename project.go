@@ -1,11 +1,22 @@
 package hal
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+)
 
 //
 // Project
 //
 
+const (
+	ProjectStatusOnTrack  = "on_track"
+	ProjectStatusAtRisk   = "at_risk"
+	ProjectStatusOffTrack = "off_track"
+)
+
 type Project struct {
 	ResourceObject
 }
@@ -22,24 +33,199 @@ func (res *Project) Id() int {
 	return res.GetInt("id")
 }
 
+func (res *Project) Identifier() string {
+	return res.GetString("identifier")
+}
+
+func (res *Project) SetIdentifier(identifier string) {
+	res.SetField("identifier", identifier)
+}
+
 func (res *Project) Name() string {
 	return res.GetString("name")
 }
 
-func (res *Project) Description() string {
-	return res.GetString("description")
+func (res *Project) SetName(name string) {
+	res.SetField("name", name)
+}
+
+func (res *Project) Description() *Formattable {
+	return res.GetFormattable("description")
+}
+
+func (res *Project) SetDescription(val *Formattable) {
+	res.SetFormattable("description", val)
+}
+
+// Archived projects are inactive.
+func (res *Project) Active() bool {
+	return res.GetBool("active")
+}
+
+func (res *Project) SetActive(active bool) {
+	res.SetField("active", active)
+}
+
+func (res *Project) Public() bool {
+	return res.GetBool("public")
+}
+
+func (res *Project) SetPublic(public bool) {
+	res.SetField("public", public)
+}
+
+// Project status: `ProjectStatusOnTrack`, `ProjectStatusAtRisk`,
+// `ProjectStatusOffTrack` or "" if not set.
+func (res *Project) Status() string {
+	if l := res.GetLink("status"); l != nil && l.Href != "" {
+		return path.Base(l.Href)
+	}
+	// Older servers send the status as a plain field.
+	return res.GetString("status")
+}
+
+// Set the project status (e.g. `ProjectStatusOnTrack`).  An empty `status`
+// clears it.
+func (res *Project) SetStatus(status string) {
+	if status == "" {
+		res.AddLink("status", Link{})
+		return
+	}
+	// Statuses are mounted next to the projects.
+	prefix := "/api/v3"
+	if l := res.GetLink("self"); l != nil && l.Href != "" {
+		prefix = path.Dir(path.Dir(l.Href))
+	}
+	res.AddLink("status", *NewLink(prefix + "/project_statuses/" + status))
+}
+
+func (res *Project) StatusExplanation() *Formattable {
+	return res.GetFormattable("statusExplanation")
+}
+
+func (res *Project) SetStatusExplanation(raw string) {
+	res.SetFormattable("statusExplanation", &Formattable{Raw: raw})
+}
+
+func (res *Project) GetParent(c *HalClient) *Project {
+	// Get embedded parent or load from a link
+	val := res.GetEmbeddedResource("parent", c)
+	if p, ok := val.(*Project); ok {
+		return p
+	}
+	return nil
+}
+
+// Move the project below `parent`.  Call `Update` to save the change.
+// A nil `parent` moves the project to the top level.
+func (res *Project) SetParent(parent *Project) {
+	if parent == nil {
+		res.AddLink("parent", Link{})
+	} else if l := parent.GetLink("self"); l != nil {
+		res.AddLink("parent", *l)
+	}
+}
+
+// Get the direct sub-projects of this project.
+func (res *Project) GetChildren(c *HalClient) (*Collection, error) {
+	filters := NewFilters().Filter("parent_id", "=", strconv.Itoa(res.Id()))
+	return c.GetFilteredCollection(c.endpoint("/projects", "projects"), filters)
+}
+
+func (res *Project) setActive(c *HalClient, active bool) (*Project, error) {
+	link := res.GetLink("updateImmediately")
+	if link == nil {
+		return nil, errors.New("No 'updateImmediately' Link")
+	}
+	patch := NewProject()
+	patch.SetActive(active)
+	newRes, err := c.Patch(link.Href, patch)
+	if err != nil {
+		return nil, err
+	}
+	if p, ok := newRes.(*Project); ok {
+		return p, nil
+	}
+	return nil, fmt.Errorf("Unknown resource type: %s", newRes.ResourceType())
+}
+
+func (res *Project) Archive(c *HalClient) (*Project, error) {
+	return res.setActive(c, false)
+}
+
+func (res *Project) Unarchive(c *HalClient) (*Project, error) {
+	return res.setActive(c, true)
 }
 
 func (res *Project) GetWorkPackages(c *HalClient) (*Collection, error) {
-	linkRes, err := res.GetLinkResource(c, "workPackages")
+	return res.getLinkCollection(c, "workPackages")
+}
+
+func (res *Project) GetCategories(c *HalClient) (*Collection, error) {
+	return res.getLinkCollection(c, "categories")
+}
+
+func (res *Project) GetVersions(c *HalClient) (*Collection, error) {
+	return res.getLinkCollection(c, "versions")
+}
+
+// Create a new version defined in this project.
+func (res *Project) CreateVersion(c *HalClient, v *Version) (*Version, error) {
+	if l := res.GetLink("self"); l != nil {
+		v.AddLink("definingProject", *l)
+	}
+	newRes, err := c.Post(c.endpoint("/versions", "versions"), v)
+	if err != nil {
+		return nil, err
+	}
+	if v, ok := newRes.(*Version); ok {
+		return v, nil
+	}
+	return nil, fmt.Errorf("Unknown resource type: %s", newRes.ResourceType())
+}
+
+//
+// Project API
+//
+
+// Get a project by id or identifier.
+func (c *HalClient) GetProject(id string) (*Project, error) {
+	res, err := c.Get(c.endpoint("/projects", "projects") + "/" + id)
+	if err != nil {
+		return nil, err
+	}
+	if p, ok := res.(*Project); ok {
+		return p, nil
+	}
+	return nil, fmt.Errorf("Unknown resource type: %s", res.ResourceType())
+}
+
+func (c *HalClient) GetProjects(filters *Filters) (*Collection, error) {
+	return c.GetFilteredCollection(c.endpoint("/projects", "projects"), filters)
+}
+
+// Validate a new project.  The returned `Form` embeds the payload, schema
+// and validation errors.
+func (c *HalClient) ProjectForm(p *Project) (*Form, error) {
+	res, err := c.Post(c.endpoint("/projects", "projects")+"/form", p)
+	if err != nil {
+		return nil, err
+	}
+	if f, ok := res.(*Form); ok {
+		return f, nil
+	}
+	return nil, fmt.Errorf("Unknown resource type: %s", res.ResourceType())
+}
+
+func (c *HalClient) CreateProject(p *Project) (*Project, error) {
+	res, err := c.Post(c.endpoint("/projects", "projects"), p)
 	if err != nil {
 		return nil, err
 	}
-	// Make sure it is a Collection
-	if col, ok := linkRes.(*Collection); ok {
-		return col, nil
+	if p, ok := res.(*Project); ok {
+		return p, nil
 	}
-	return nil, fmt.Errorf("Unknown resource type: %s", linkRes.ResourceType())
+	return nil, fmt.Errorf("Unknown resource type: %s", res.ResourceType())
 }
 
 // Register Factories
@@ -0,0 +1,268 @@
+package hal
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+)
+
+//
+// Query
+//
+// A saved work package view.  Filters, columns, sortBy and groupBy are
+// referenced by links like "/api/v3/queries/filters/status", so their
+// setters need the client to know the API root.
+//
+
+type Query struct {
+	ResourceObject
+}
+
+func NewQuery() *Query {
+	return &Query{
+		ResourceObject{
+			Type: "Query",
+		},
+	}
+}
+
+func (res *Query) Id() int {
+	return res.GetInt("id")
+}
+
+func (res *Query) Name() string {
+	return res.GetString("name")
+}
+
+func (res *Query) SetName(name string) {
+	res.SetField("name", name)
+}
+
+func (res *Query) Public() bool {
+	return res.GetBool("public")
+}
+
+func (res *Query) SetPublic(public bool) {
+	res.SetField("public", public)
+}
+
+func (res *Query) Hidden() bool {
+	return res.GetBool("hidden")
+}
+
+func (res *Query) SetHidden(hidden bool) {
+	res.SetField("hidden", hidden)
+}
+
+func (res *Query) Starred() bool {
+	return res.GetBool("starred")
+}
+
+func (res *Query) SetProject(p *Project) {
+	if l := p.GetLink("self"); l != nil {
+		res.AddLink("project", *l)
+	}
+}
+
+// Get the id from the end of a link, e.g. "status" from
+// "/api/v3/queries/filters/status".
+func linkId(l *Link) string {
+	if l == nil || l.Href == "" {
+		return ""
+	}
+	id, err := url.PathUnescape(path.Base(l.Href))
+	if err != nil {
+		return path.Base(l.Href)
+	}
+	return id
+}
+
+func linkIds(links []Link) []string {
+	ids := make([]string, 0, len(links))
+	for i := range links {
+		if id := linkId(&links[i]); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// Link to a query attribute, e.g. ("filters", "status") to
+// "/api/v3/queries/filters/status".
+func queryLink(c *HalClient, kind string, id string) *Link {
+	return NewLink(c.apiRootPath() + "/queries/" + kind + "/" + url.PathEscape(id))
+}
+
+func queryLinks(c *HalClient, kind string, ids []string) []Link {
+	links := make([]Link, 0, len(ids))
+	for _, id := range ids {
+		links = append(links, *queryLink(c, kind, id))
+	}
+	return links
+}
+
+func (res *Query) Filters() *Filters {
+	filters := NewFilters()
+	arr, _ := res.GetField("filters").([]interface{})
+	for _, val := range arr {
+		m, ok := val.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		// Decode the filter instance's links.
+		inst := &ResourceObject{}
+		if err := inst.decodeMap(m); err != nil {
+			continue
+		}
+		name := linkId(inst.GetLink("filter"))
+		operator := linkId(inst.GetLink("operator"))
+		values := []interface{}{}
+		if vals, ok := inst.GetField("values").([]interface{}); ok {
+			values = vals
+		} else {
+			for _, id := range linkIds(inst.GetLinks("values")) {
+				values = append(values, id)
+			}
+		}
+		filters.Filter(name, operator, values...)
+	}
+	return filters
+}
+
+func (res *Query) SetFilters(c *HalClient, filters *Filters) {
+	arr := make([]interface{}, 0, len(filters.FilterList))
+	for _, filter := range filters.FilterList {
+		for name, op := range filter {
+			values := op.Values
+			if values == nil {
+				values = []interface{}{}
+			}
+			arr = append(arr, map[string]interface{}{
+				"_links": map[string]interface{}{
+					"filter":   queryLink(c, "filters", name),
+					"operator": queryLink(c, "operators", op.Operator),
+				},
+				"values": values,
+			})
+		}
+	}
+	res.SetField("filters", arr)
+}
+
+// Names of the displayed columns.
+func (res *Query) Columns() []string {
+	return linkIds(res.GetLinks("columns"))
+}
+
+func (res *Query) SetColumns(c *HalClient, columns ...string) {
+	res.SetLinks("columns", queryLinks(c, "columns", columns))
+}
+
+// Sort criteria, e.g. "id-asc".
+func (res *Query) SortBy() []string {
+	return linkIds(res.GetLinks("sortBy"))
+}
+
+func (res *Query) SetSortBy(c *HalClient, sortBy ...string) {
+	res.SetLinks("sortBy", queryLinks(c, "sort_bys", sortBy))
+}
+
+func (res *Query) GroupBy() string {
+	return linkId(res.GetLink("groupBy"))
+}
+
+func (res *Query) SetGroupBy(c *HalClient, groupBy string) {
+	res.AddLink("groupBy", *queryLink(c, "group_bys", groupBy))
+}
+
+// The work packages matching the query.
+func (res *Query) Results(c *HalClient) *Collection {
+	// Get embedded results or load from a link
+	val := res.GetEmbeddedResource("results", c)
+	if col, ok := val.(*Collection); ok {
+		return col
+	}
+	return nil
+}
+
+func (res *Query) starAction(c *HalClient, name string) (*Query, error) {
+	link := res.GetLink(name)
+	if link == nil {
+		return nil, fmt.Errorf("No '%s' Link", name)
+	}
+	linkRes, err := c.LinkRequest(link, nil)
+	if err != nil {
+		return nil, err
+	}
+	if q, ok := linkRes.(*Query); ok {
+		return q, nil
+	}
+	return nil, fmt.Errorf("Unknown resource type: %s", linkRes.ResourceType())
+}
+
+func (res *Query) Star(c *HalClient) (*Query, error) {
+	return res.starAction(c, "star")
+}
+
+func (res *Query) Unstar(c *HalClient) (*Query, error) {
+	return res.starAction(c, "unstar")
+}
+
+//
+// Queries API
+//
+
+func (c *HalClient) CreateQuery(q *Query) (*Query, error) {
+	res, err := c.Post(c.endpoint("/queries", "queries"), q)
+	if err != nil {
+		return nil, err
+	}
+	if q, ok := res.(*Query); ok {
+		return q, nil
+	}
+	return nil, fmt.Errorf("Unknown resource type: %s", res.ResourceType())
+}
+
+// Get a saved query with one page of results.  `offset` is the page number
+// starting at 1, a `pageSize` of 0 uses the server's default.
+func (c *HalClient) GetQuery(id int, offset int, pageSize int) (*Query, error) {
+	params := url.Values{}
+	if offset > 0 {
+		params.Set("offset", strconv.Itoa(offset))
+	}
+	if pageSize > 0 {
+		params.Set("pageSize", strconv.Itoa(pageSize))
+	}
+	path := c.endpoint("/queries", "queries") + "/" + strconv.Itoa(id)
+	if len(params) > 0 {
+		path += "?" + params.Encode()
+	}
+	res, err := c.Get(path)
+	if err != nil {
+		return nil, err
+	}
+	if q, ok := res.(*Query); ok {
+		return q, nil
+	}
+	return nil, fmt.Errorf("Unknown resource type: %s", res.ResourceType())
+}
+
+// Run a saved query.  Use `NextPage` on the results to get more pages.
+func (c *HalClient) RunQuery(id int, offset int, pageSize int) (*Collection, error) {
+	q, err := c.GetQuery(id, offset, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	if col := q.Results(c); col != nil {
+		return col, nil
+	}
+	return nil, fmt.Errorf("Query %d has no results", id)
+}
+
+// Register Factories
+func init() {
+	resourceTypes["Query"] = func() Resource {
+		return NewQuery()
+	}
+}
@@ -0,0 +1,74 @@
+package hal
+
+import (
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/url"
+	"strings"
+)
+
+//
+// Server-side rendering
+//
+// Preview text as the server would render it, e.g. with links to work
+// packages resolved.
+//
+
+func (c *HalClient) render(format string, text string, context *Link) (*Formattable, error) {
+	path := c.apiRootPath() + "/render/" + format
+	if context != nil && context.Href != "" {
+		path += "?context=" + url.QueryEscape(context.Href)
+	}
+	req, err := c.newRequestContent("POST", path, "text/plain; charset=utf-8", strings.NewReader(text))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/html, application/json")
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return nil, responseError(resp)
+	}
+
+	ct, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if ct == "application/json" || ct == "application/hal+json" {
+		res, err := Decode(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		obj, ok := res.(*ResourceObject)
+		if !ok {
+			return nil, fmt.Errorf("Unknown resource type: %s", res.ResourceType())
+		}
+		f := &Formattable{}
+		if err := f.decodeMap(obj.fields); err != nil {
+			return nil, err
+		}
+		if f.Raw == "" {
+			f.Raw = text
+		}
+		return f, nil
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return NewFormattable(format, text, string(body)), nil
+}
+
+// Render markdown to HTML.  `context` is the resource the text belongs to
+// (e.g. a work package), used to resolve relative references.  It can be nil.
+func (c *HalClient) RenderMarkdown(text string, context *Link) (*Formattable, error) {
+	return c.render("markdown", text, context)
+}
+
+// Render plain text to HTML.
+func (c *HalClient) RenderPlain(text string) (*Formattable, error) {
+	return c.render("plain", text, nil)
+}
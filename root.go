@@ -0,0 +1,95 @@
+package hal
+
+import (
+	"fmt"
+)
+
+//
+// Root
+//
+// The API entry point links to the other endpoints.
+//
+
+type Root struct {
+	ResourceObject
+}
+
+func NewRoot() *Root {
+	return &Root{
+		ResourceObject{
+			Type: "Root",
+		},
+	}
+}
+
+func (res *Root) InstanceName() string {
+	return res.GetString("instanceName")
+}
+
+func (res *Root) CoreVersion() string {
+	return res.GetString("coreVersion")
+}
+
+// Get the API entry point.  The Root resource is cached after the first
+// successful request until `SetAPIRoot` or `SetAPIKey` is called.
+func (c *HalClient) Root() (*Root, error) {
+	c.rootLock.Lock()
+	root, gen, apiRoot := c.root, c.rootGen, c.apiRoot
+	c.rootLock.Unlock()
+	if root != nil {
+		return root, nil
+	}
+
+	// Don't hold the lock during the request.
+	res, err := c.Get(apiRoot)
+	if err != nil {
+		return nil, err
+	}
+	root, ok := res.(*Root)
+	if !ok {
+		return nil, fmt.Errorf("Unknown resource type: %s", res.ResourceType())
+	}
+
+	c.rootLock.Lock()
+	defer c.rootLock.Unlock()
+	// Don't cache it if the client changed during the request.
+	if c.rootGen == gen && c.root == nil {
+		c.root = root
+	}
+	return root, nil
+}
+
+// Find an endpoint from the Root links `rels`.  Falls back to `path` below
+// the API root when the Root isn't available or doesn't have the link.
+func (c *HalClient) endpoint(path string, rels ...string) string {
+	if len(rels) == 0 {
+		return c.apiRootPath() + path
+	}
+	if root, err := c.Root(); err == nil {
+		for _, rel := range rels {
+			if l := root.GetLink(rel); l != nil && l.Href != "" && !l.Templated {
+				return l.Href
+			}
+		}
+	}
+	return c.apiRootPath() + path
+}
+
+func (c *HalClient) GetStatuses() (*Collection, error) {
+	return c.GetCollection(c.endpoint("/statuses", "statuses"))
+}
+
+func (c *HalClient) GetPriorities() (*Collection, error) {
+	return c.GetCollection(c.endpoint("/priorities", "priorities"))
+}
+
+func (c *HalClient) GetTypes() (*Collection, error) {
+	return c.GetCollection(c.endpoint("/types", "types"))
+}
+
+// Register Factories
+func init() {
+	resourceTypes["Root"] = func() Resource {
+		return NewRoot()
+	}
+}
@@ -0,0 +1,144 @@
+package hal
+
+import (
+	"sort"
+	"strings"
+)
+
+//
+// Form
+//
+// Returned when validating a resource before creating/updating it.
+//
+
+type Form struct {
+	ResourceObject
+}
+
+func NewForm() *Form {
+	return &Form{
+		ResourceObject{
+			Type: "Form",
+		},
+	}
+}
+
+func (res *Form) Payload() Resource {
+	return res.GetEmbeddedResource("payload", nil)
+}
+
+func (res *Form) Schema() *Schema {
+	val := res.GetEmbeddedResource("schema", nil)
+	if s, ok := val.(*Schema); ok {
+		return s
+	}
+	return nil
+}
+
+// Validation errors by field name.
+func (res *Form) ValidationErrors() map[string]*Error {
+	errs := make(map[string]*Error)
+	val, ok := res.GetEmbeddedResource("validationErrors", nil).(*ResourceObject)
+	if !ok {
+		return errs
+	}
+	for name, field := range val.fields {
+		if m, ok := field.(map[string]interface{}); ok {
+			resErr := NewError()
+			if err := resErr.decodeMap(m); err == nil {
+				errs[name] = resErr
+			}
+		}
+	}
+	return errs
+}
+
+//
+// Schema
+//
+
+type Schema struct {
+	ResourceObject
+}
+
+func NewSchema() *Schema {
+	return &Schema{
+		ResourceObject{
+			Type: "Schema",
+		},
+	}
+}
+
+type SchemaField struct {
+	Name       string
+	Type       string
+	Label      string
+	Required   bool
+	HasDefault bool
+	Writable   bool
+
+	// Embedded allowed values, or a link to load them from.
+	AllowedValues     []Resource
+	AllowedValuesLink *Link
+}
+
+// Names of the fields described by the schema.
+func (res *Schema) Fields() []string {
+	names := make([]string, 0, len(res.fields))
+	for name, val := range res.fields {
+		if strings.HasPrefix(name, "_") {
+			continue
+		}
+		if _, ok := val.(map[string]interface{}); ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (res *Schema) Field(name string) *SchemaField {
+	m, ok := res.GetField(name).(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	obj := &ResourceObject{}
+	if err := obj.decodeMap(m); err != nil {
+		return nil
+	}
+	return &SchemaField{
+		Name:              name,
+		Type:              obj.GetString("type"),
+		Label:             obj.GetString("name"),
+		Required:          obj.GetBool("required"),
+		HasDefault:        obj.GetBool("hasDefault"),
+		Writable:          obj.GetBool("writable"),
+		AllowedValues:     obj.GetEmbeddedResourceList("allowedValues"),
+		AllowedValuesLink: obj.GetLink("allowedValues"),
+	}
+}
+
+// Get the allowed values of a field, loading them if they aren't embedded.
+func (f *SchemaField) GetAllowedValues(c *HalClient) ([]Resource, error) {
+	if f.AllowedValues != nil || f.AllowedValuesLink == nil {
+		return f.AllowedValues, nil
+	}
+	res, err := c.LinkGet(f.AllowedValuesLink)
+	if err != nil {
+		return nil, err
+	}
+	if col, ok := res.(*Collection); ok {
+		return col.AllItems(c)
+	}
+	return []Resource{res}, nil
+}
+
+// Register Factories
+func init() {
+	resourceTypes["Form"] = func() Resource {
+		return NewForm()
+	}
+	resourceTypes["Schema"] = func() Resource {
+		return NewSchema()
+	}
+}
@@ -0,0 +1,44 @@
+package hal
+
+//
+// Status
+//
+
+type Status struct {
+	ResourceObject
+}
+
+func NewStatus() *Status {
+	return &Status{
+		ResourceObject{
+			Type: "Status",
+		},
+	}
+}
+
+func (res *Status) Id() int {
+	return res.GetInt("id")
+}
+
+func (res *Status) Name() string {
+	return res.GetString("name")
+}
+
+func (res *Status) Position() int {
+	return res.GetInt("position")
+}
+
+func (res *Status) IsClosed() bool {
+	return res.GetBool("isClosed")
+}
+
+func (res *Status) IsDefault() bool {
+	return res.GetBool("isDefault")
+}
+
+// Register Factories
+func init() {
+	resourceTypes["Status"] = func() Resource {
+		return NewStatus()
+	}
+}
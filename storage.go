@@ -0,0 +1,203 @@
+package hal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+//
+// Storage
+//
+// External file storage (e.g. Nextcloud) connected to projects.
+//
+
+type Storage struct {
+	ResourceObject
+}
+
+func NewStorage() *Storage {
+	return &Storage{
+		ResourceObject{
+			Type: "Storage",
+		},
+	}
+}
+
+func (res *Storage) Id() int {
+	return res.GetInt("id")
+}
+
+func (res *Storage) Name() string {
+	return res.GetString("name")
+}
+
+// Storage type, e.g. "urn:openproject-org:api:v3:storages:Nextcloud".
+func (res *Storage) StorageType() string {
+	if l := res.GetLink("type"); l != nil {
+		return l.Href
+	}
+	return ""
+}
+
+// URL of the storage host.
+func (res *Storage) Origin() string {
+	if l := res.GetLink("origin"); l != nil {
+		return l.Href
+	}
+	return ""
+}
+
+func (c *HalClient) GetStorages() (*Collection, error) {
+	return c.GetCollection(c.endpoint("/storages", "storages"))
+}
+
+func (c *HalClient) GetStorage(id int) (*Storage, error) {
+	res, err := c.Get(c.endpoint("/storages", "storages") + "/" + strconv.Itoa(id))
+	if err != nil {
+		return nil, err
+	}
+	if s, ok := res.(*Storage); ok {
+		return s, nil
+	}
+	return nil, fmt.Errorf("Unknown resource type: %s", res.ResourceType())
+}
+
+//
+// FileLink
+//
+// A link from a work package to a file in a storage.
+//
+
+// Metadata of the linked file from the storage.
+type FileLinkOriginData struct {
+	Id                 string     `json:"id"`
+	Name               string     `json:"name"`
+	MimeType           string     `json:"mimeType,omitempty"`
+	Size               int64      `json:"size,omitempty"`
+	CreatedAt          *time.Time `json:"createdAt,omitempty"`
+	LastModifiedAt     *time.Time `json:"lastModifiedAt,omitempty"`
+	CreatedByName      string     `json:"createdByName,omitempty"`
+	LastModifiedByName string     `json:"lastModifiedByName,omitempty"`
+}
+
+type FileLink struct {
+	ResourceObject
+}
+
+func NewFileLink() *FileLink {
+	return &FileLink{
+		ResourceObject{
+			Type: "FileLink",
+		},
+	}
+}
+
+func (res *FileLink) Id() int {
+	return res.GetInt("id")
+}
+
+func (res *FileLink) OriginData() *FileLinkOriginData {
+	val := res.GetField("originData")
+	if val == nil {
+		return nil
+	}
+	buf, err := json.Marshal(val)
+	if err != nil {
+		return nil
+	}
+	origin := &FileLinkOriginData{}
+	if err := json.Unmarshal(buf, origin); err != nil {
+		return nil
+	}
+	return origin
+}
+
+func (res *FileLink) SetOriginData(origin *FileLinkOriginData) {
+	res.SetField("originData", origin)
+}
+
+func (res *FileLink) SetStorage(s *Storage) {
+	if l := s.GetLink("self"); l != nil {
+		res.AddLink("storage", *l)
+	}
+}
+
+func (res *FileLink) GetStorage(c *HalClient) *Storage {
+	// Get embedded storage or load from a link
+	val := res.GetEmbeddedResource("storage", c)
+	if s, ok := val.(*Storage); ok {
+		return s
+	}
+	return nil
+}
+
+// The resource (e.g. work package) the file is linked to.
+func (res *FileLink) GetContainer(c *HalClient) Resource {
+	return res.GetEmbeddedResource("container", c)
+}
+
+func (res *FileLink) GetCreator(c *HalClient) *User {
+	// Get embedded creator or load from a link
+	val := res.GetEmbeddedResource("creator", c)
+	if u, ok := val.(*User); ok {
+		return u
+	}
+	return nil
+}
+
+// URL to open the file in the storage's web interface.
+func (res *FileLink) OriginOpenURL() string {
+	if l := res.GetLink("staticOriginOpen"); l != nil {
+		return l.Href
+	}
+	if l := res.GetLink("originOpen"); l != nil {
+		return l.Href
+	}
+	return ""
+}
+
+//
+// Work package file links
+//
+
+func (res *WorkPackage) GetFileLinks(c *HalClient) (*Collection, error) {
+	return res.getLinkCollection(c, "fileLinks")
+}
+
+// Link files to this work package.  Each file link needs its origin data
+// and storage set.
+func (res *WorkPackage) CreateFileLinks(c *HalClient, links ...*FileLink) (*Collection, error) {
+	link := res.GetLink("fileLinks")
+	if link == nil {
+		return nil, errors.New("No 'fileLinks' Link")
+	}
+	elements := make([]Resource, 0, len(links))
+	for _, l := range links {
+		elements = append(elements, l)
+	}
+	col := NewCollection()
+	col.embedded = map[string]interface{}{
+		"elements": elements,
+	}
+	newRes, err := c.Post(link.Href, col)
+	if err != nil {
+		return nil, err
+	}
+	if col, ok := newRes.(*Collection); ok {
+		return col, nil
+	}
+	return nil, fmt.Errorf("Unknown resource type: %s", newRes.ResourceType())
+}
+
+// Register Factories
+func init() {
+	resourceTypes["Storage"] = func() Resource {
+		return NewStorage()
+	}
+	resourceTypes["FileLink"] = func() Resource {
+		return NewFileLink()
+	}
+}
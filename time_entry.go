@@ -1,6 +1,9 @@
 package hal
 
 import (
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 )
 
@@ -49,25 +52,15 @@ func (res *TimeEntry) Id() int {
 }
 
 func (res *TimeEntry) Comment() *Formattable {
-	f, err := DecodeFormattable(res.GetField("comment"))
-	if err != nil {
-		return nil
-	}
-	return f
+	return res.GetFormattable("comment")
 }
 
 func (res *TimeEntry) SetComment(format, raw, html string) {
-	// TODO: add Get/Set Formattable methods to ResourceObject
-	val := make(map[string]interface{})
-	val["format"] = format
-	val["raw"] = raw
-	val["html"] = html
-
-	res.SetField("comment", val)
+	res.SetFormattable("comment", NewFormattable(format, raw, html))
 }
 
 func (res *TimeEntry) SpentOn() *time.Time {
-	if dt, err := res.GetDateTime("spentOn"); err == nil {
+	if dt, err := res.GetDate("spentOn"); err == nil {
 		return &dt
 	}
 	return nil
@@ -88,11 +81,194 @@ func (res *TimeEntry) SetHours(hours time.Duration) {
 	res.SetDuration("hours", hours)
 }
 
-func (res *TimeEntry) SetActivity(activity string) {
+func (res *TimeEntry) SetActivity(activity *TimeEntriesActivity) {
+	if l := activity.GetLink("self"); l != nil {
+		res.AddLink("activity", *l)
+	}
+}
+
+func (res *TimeEntry) SetActivityHref(activity string) {
 	actLink := NewLink(activity)
 	res.AddLink("activity", *actLink)
 }
 
+func (res *TimeEntry) GetUser(c *HalClient) *User {
+	// Get embedded user or load from a link
+	val := res.GetEmbeddedResource("user", c)
+	if u, ok := val.(*User); ok {
+		return u
+	}
+	return nil
+}
+
+func (res *TimeEntry) GetProject(c *HalClient) *Project {
+	// Get embedded project or load from a link
+	val := res.GetEmbeddedResource("project", c)
+	if p, ok := val.(*Project); ok {
+		return p
+	}
+	return nil
+}
+
+func (res *TimeEntry) GetWorkPackage(c *HalClient) *WorkPackage {
+	// Get embedded work package or load from a link
+	val := res.GetEmbeddedResource("workPackage", c)
+	if wp, ok := val.(*WorkPackage); ok {
+		return wp
+	}
+	return nil
+}
+
+func (res *TimeEntry) GetActivity(c *HalClient) *TimeEntriesActivity {
+	// Get embedded activity or load from a link
+	val := res.GetEmbeddedResource("activity", c)
+	if act, ok := val.(*TimeEntriesActivity); ok {
+		return act
+	}
+	return nil
+}
+
+//
+// Time entries API
+//
+
+// List time entries, filtered by e.g. `user`, `project`, `work_package`,
+// `activity` or `spent_on`:
+//
+//	NewFilters().Filter("user", "=", "4").DateRange("spent_on", from, to)
+func (c *HalClient) GetTimeEntries(filters *Filters) (*Collection, error) {
+	return c.GetFilteredCollection(c.endpoint("/time_entries", "timeEntries", "time_entries"), filters)
+}
+
+// Load all pages of time entries matching `filters`.
+func (c *HalClient) GetAllTimeEntries(filters *Filters) ([]*TimeEntry, error) {
+	col, err := c.GetTimeEntries(filters)
+	if err != nil {
+		return nil, err
+	}
+	items, err := col.AllItems(c)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]*TimeEntry, 0, len(items))
+	for _, item := range items {
+		te, ok := item.(*TimeEntry)
+		if !ok {
+			return nil, fmt.Errorf("Unknown resource type: %s", item.ResourceType())
+		}
+		entries = append(entries, te)
+	}
+	return entries, nil
+}
+
+// Get the activities that can be used for time entries in `project`.
+func (c *HalClient) GetTimeEntryActivities(project *Project) ([]*TimeEntriesActivity, error) {
+	te := NewTimeEntry()
+	if l := project.GetLink("self"); l != nil {
+		te.AddLink("project", *l)
+	}
+	res, err := c.Post(c.endpoint("/time_entries", "timeEntries", "time_entries")+"/form", te)
+	if err != nil {
+		return nil, err
+	}
+	form, ok := res.(*Form)
+	if !ok {
+		return nil, fmt.Errorf("Unknown resource type: %s", res.ResourceType())
+	}
+	schema := form.Schema()
+	if schema == nil {
+		return nil, errors.New("Time entry form has no schema")
+	}
+	field := schema.Field("activity")
+	if field == nil {
+		return nil, errors.New("Time entry schema has no 'activity' field")
+	}
+	items, err := field.GetAllowedValues(c)
+	if err != nil {
+		return nil, err
+	}
+	activities := make([]*TimeEntriesActivity, 0, len(items))
+	for _, item := range items {
+		if act, ok := item.(*TimeEntriesActivity); ok {
+			activities = append(activities, act)
+		}
+	}
+	return activities, nil
+}
+
+// Find an activity by name (case insensitive).
+func FindTimeEntryActivity(activities []*TimeEntriesActivity, name string) *TimeEntriesActivity {
+	for _, act := range activities {
+		if strings.EqualFold(act.Name(), name) {
+			return act
+		}
+	}
+	return nil
+}
+
+//
+// Time entry reports
+//
+
+const (
+	GroupByUser        = "user"
+	GroupByProject     = "project"
+	GroupByActivity    = "activity"
+	GroupByWorkPackage = "workPackage"
+	// ISO week of `spentOn`, e.g. "2019-W36"
+	GroupByWeek = "week"
+)
+
+func timeEntryGroupKey(te *TimeEntry, groupBy string) string {
+	if groupBy == GroupByWeek {
+		if spentOn := te.SpentOn(); spentOn != nil {
+			year, week := spentOn.ISOWeek()
+			return fmt.Sprintf("%d-W%02d", year, week)
+		}
+		return ""
+	}
+	if l := te.GetLink(groupBy); l != nil {
+		return l.Href
+	}
+	return ""
+}
+
+// Sum the hours of the time entries grouped by `GroupByUser`,
+// `GroupByProject`, `GroupByActivity`, `GroupByWorkPackage` or `GroupByWeek`.
+// Groups are keyed by the linked resource's href (or the week), use
+// `TimeEntryGroupTitles` for display names.  Entries without a value for
+// the group are summed under "".
+func SumTimeEntries(entries []*TimeEntry, groupBy string) map[string]time.Duration {
+	sums := make(map[string]time.Duration)
+	for _, te := range entries {
+		hours := te.Hours()
+		if hours == nil {
+			continue
+		}
+		sums[timeEntryGroupKey(te, groupBy)] += *hours
+	}
+	return sums
+}
+
+// Get the display names of the groups returned by `SumTimeEntries`, taken
+// from the link titles.  Groups without a title are named by their key.
+func TimeEntryGroupTitles(entries []*TimeEntry, groupBy string) map[string]string {
+	titles := make(map[string]string)
+	for _, te := range entries {
+		key := timeEntryGroupKey(te, groupBy)
+		if titles[key] != "" && titles[key] != key {
+			continue
+		}
+		titles[key] = key
+		if groupBy != GroupByWeek {
+			if l := te.GetLink(groupBy); l != nil && l.Title != "" {
+				titles[key] = l.Title
+			}
+		}
+	}
+	return titles
+}
+
 // Register Factories
 func init() {
 	resourceTypes["TimeEntriesActivity"] = func() Resource {
@@ -1,11 +1,22 @@
 package hal
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
 
 //
 // User
 //
 
+const (
+	UserStatusActive     = "active"
+	UserStatusRegistered = "registered"
+	UserStatusLocked     = "locked"
+	UserStatusInvited    = "invited"
+)
+
 type User struct {
 	ResourceObject
 }
@@ -30,18 +41,90 @@ func (res *User) FirstName() string {
 	return res.GetString("firstName")
 }
 
+func (res *User) SetFirstName(firstName string) {
+	res.SetField("firstName", firstName)
+}
+
 func (res *User) LastName() string {
 	return res.GetString("lastName")
 }
 
+func (res *User) SetLastName(lastName string) {
+	res.SetField("lastName", lastName)
+}
+
 func (res *User) Login() string {
 	return res.GetString("login")
 }
 
+func (res *User) SetLogin(login string) {
+	res.SetField("login", login)
+}
+
 func (res *User) Email() string {
 	return res.GetString("email")
 }
 
+func (res *User) SetEmail(email string) {
+	res.SetField("email", email)
+}
+
+// Only used when creating users.
+func (res *User) SetPassword(password string) {
+	res.SetField("password", password)
+}
+
+func (res *User) Status() string {
+	return res.GetString("status")
+}
+
+func (res *User) SetStatus(status string) {
+	res.SetField("status", status)
+}
+
+func (res *User) Admin() bool {
+	return res.GetBool("admin")
+}
+
+func (res *User) SetAdmin(admin bool) {
+	res.SetField("admin", admin)
+}
+
+func (res *User) Avatar() string {
+	return res.GetString("avatar")
+}
+
+func (res *User) Language() string {
+	return res.GetString("language")
+}
+
+func (res *User) SetLanguage(language string) {
+	res.SetField("language", language)
+}
+
+func (res *User) lockAction(c *HalClient, name string) (*User, error) {
+	link := res.GetLink(name)
+	if link == nil {
+		return nil, fmt.Errorf("No '%s' Link", name)
+	}
+	linkRes, err := c.LinkRequest(link, nil)
+	if err != nil {
+		return nil, err
+	}
+	if u, ok := linkRes.(*User); ok {
+		return u, nil
+	}
+	return nil, fmt.Errorf("Unknown resource type: %s", linkRes.ResourceType())
+}
+
+func (res *User) Lock(c *HalClient) (*User, error) {
+	return res.lockAction(c, "lock")
+}
+
+func (res *User) Unlock(c *HalClient) (*User, error) {
+	return res.lockAction(c, "unlock")
+}
+
 //
 // UserPreferences
 //
@@ -70,6 +153,58 @@ func (res *UserPreferences) GetUser(c *HalClient) (*User, error) {
 	return nil, fmt.Errorf("Unknown resource type: %s", linkRes.ResourceType())
 }
 
+//
+// Users API
+//
+
+func (c *HalClient) getUser(path string) (*User, error) {
+	res, err := c.Get(path)
+	if err != nil {
+		return nil, err
+	}
+	if u, ok := res.(*User); ok {
+		return u, nil
+	}
+	return nil, fmt.Errorf("Unknown resource type: %s", res.ResourceType())
+}
+
+func (c *HalClient) GetUser(id int) (*User, error) {
+	return c.getUser(c.endpoint("/users", "users") + "/" + strconv.Itoa(id))
+}
+
+// Get the user the client is authenticated as.
+func (c *HalClient) Me() (*User, error) {
+	return c.getUser(c.endpoint("/users/me", "user"))
+}
+
+func (c *HalClient) GetUsers(filters *Filters) (*Collection, error) {
+	return c.GetFilteredCollection(c.endpoint("/users", "users"), filters)
+}
+
+func (c *HalClient) FindUserByLogin(login string) (*User, error) {
+	col, err := c.GetUsers(NewFilters().Filter("login", "=", login))
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range col.Items() {
+		if u, ok := item.(*User); ok && u.Login() == login {
+			return u, nil
+		}
+	}
+	return nil, errors.New("No user with login: " + login)
+}
+
+func (c *HalClient) CreateUser(u *User) (*User, error) {
+	res, err := c.Post(c.endpoint("/users", "users"), u)
+	if err != nil {
+		return nil, err
+	}
+	if u, ok := res.(*User); ok {
+		return u, nil
+	}
+	return nil, fmt.Errorf("Unknown resource type: %s", res.ResourceType())
+}
+
 // Register Factories
 func init() {
 	resourceTypes["User"] = func() Resource {
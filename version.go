@@ -0,0 +1,116 @@
+package hal
+
+import (
+	"strconv"
+	"time"
+)
+
+//
+// Version
+//
+
+const (
+	VersionStatusOpen   = "open"
+	VersionStatusLocked = "locked"
+	VersionStatusClosed = "closed"
+)
+
+const (
+	VersionSharingNone        = "none"
+	VersionSharingDescendants = "descendants"
+	VersionSharingHierarchy   = "hierarchy"
+	VersionSharingTree        = "tree"
+	VersionSharingSystem      = "system"
+)
+
+type Version struct {
+	ResourceObject
+}
+
+func NewVersion() *Version {
+	return &Version{
+		ResourceObject{
+			Type: "Version",
+		},
+	}
+}
+
+func (res *Version) Id() int {
+	return res.GetInt("id")
+}
+
+func (res *Version) Name() string {
+	return res.GetString("name")
+}
+
+func (res *Version) SetName(name string) {
+	res.SetField("name", name)
+}
+
+func (res *Version) Description() *Formattable {
+	return res.GetFormattable("description")
+}
+
+func (res *Version) StartDate() *time.Time {
+	if dt, err := res.GetDate("startDate"); err == nil {
+		return &dt
+	}
+	return nil
+}
+
+func (res *Version) SetStartDate(startDate time.Time) {
+	res.SetDate("startDate", startDate)
+}
+
+func (res *Version) EndDate() *time.Time {
+	if dt, err := res.GetDate("endDate"); err == nil {
+		return &dt
+	}
+	return nil
+}
+
+func (res *Version) SetEndDate(endDate time.Time) {
+	res.SetDate("endDate", endDate)
+}
+
+func (res *Version) Status() string {
+	return res.GetString("status")
+}
+
+func (res *Version) SetStatus(status string) {
+	res.SetField("status", status)
+}
+
+func (res *Version) Sharing() string {
+	return res.GetString("sharing")
+}
+
+func (res *Version) SetSharing(sharing string) {
+	res.SetField("sharing", sharing)
+}
+
+func (res *Version) GetDefiningProject(c *HalClient) *Project {
+	// Get embedded project or load from a link
+	val := res.GetEmbeddedResource("definingProject", c)
+	if p, ok := val.(*Project); ok {
+		return p
+	}
+	return nil
+}
+
+func (res *Version) GetAvailableInProjects(c *HalClient) (*Collection, error) {
+	return res.getLinkCollection(c, "availableInProjects")
+}
+
+// Get the work packages assigned to this version.
+func (res *Version) GetWorkPackages(c *HalClient) (*Collection, error) {
+	filters := NewFilters().Filter("version", "=", strconv.Itoa(res.Id()))
+	return c.GetFilteredCollection(c.endpoint("/work_packages", "workPackages"), filters)
+}
+
+// Register Factories
+func init() {
+	resourceTypes["Version"] = func() Resource {
+		return NewVersion()
+	}
+}
@@ -0,0 +1,66 @@
+package hal
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+//
+// WikiPage
+//
+
+type WikiPage struct {
+	ResourceObject
+}
+
+func NewWikiPage() *WikiPage {
+	return &WikiPage{
+		ResourceObject{
+			Type: "WikiPage",
+		},
+	}
+}
+
+func (res *WikiPage) Id() int {
+	return res.GetInt("id")
+}
+
+func (res *WikiPage) Title() string {
+	return res.GetString("title")
+}
+
+func (res *WikiPage) GetProject(c *HalClient) *Project {
+	// Get embedded project or load from a link
+	val := res.GetEmbeddedResource("project", c)
+	if p, ok := val.(*Project); ok {
+		return p
+	}
+	return nil
+}
+
+func (res *WikiPage) GetAttachments(c *HalClient) *Collection {
+	return res.getAttachments(c)
+}
+
+func (res *WikiPage) AddAttachment(c *HalClient, fileName, contentType, description string, r io.Reader) (*Attachment, error) {
+	return c.AddAttachment(res, fileName, contentType, description, r)
+}
+
+func (c *HalClient) GetWikiPage(id int) (*WikiPage, error) {
+	res, err := c.Get(c.endpoint("/wiki_pages", "wikiPages") + "/" + strconv.Itoa(id))
+	if err != nil {
+		return nil, err
+	}
+	if p, ok := res.(*WikiPage); ok {
+		return p, nil
+	}
+	return nil, fmt.Errorf("Unknown resource type: %s", res.ResourceType())
+}
+
+// Register Factories
+func init() {
+	resourceTypes["WikiPage"] = func() Resource {
+		return NewWikiPage()
+	}
+}
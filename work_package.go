@@ -1,6 +1,10 @@
 package hal
 
-import "time"
+import (
+	"errors"
+	"io"
+	"time"
+)
 
 //
 // WorkPackage
@@ -23,11 +27,11 @@ func (res *WorkPackage) Id() int {
 }
 
 func (res *WorkPackage) Description() *Formattable {
-	f, err := DecodeFormattable(res.GetField("description"))
-	if err != nil {
-		return nil
-	}
-	return f
+	return res.GetFormattable("description")
+}
+
+func (res *WorkPackage) SetDescription(val *Formattable) {
+	res.SetFormattable("description", val)
 }
 
 func (res *WorkPackage) Subject() string {
@@ -35,12 +39,30 @@ func (res *WorkPackage) Subject() string {
 }
 
 func (res *WorkPackage) GetAttachments(c *HalClient) *Collection {
-	// Get embedded attachments or load from a link
-	val := res.GetEmbeddedResource("attachments", c)
-	if col, ok := val.(*Collection); ok {
-		return col
+	return res.getAttachments(c)
+}
+
+// Download all attachments into `dir`, see `DownloadAttachments`.
+func (res *WorkPackage) DownloadAttachments(c *HalClient, dir string, opts *DownloadOptions) ([]DownloadResult, error) {
+	col := res.GetAttachments(c)
+	if col == nil {
+		return nil, errors.New("Failed to load attachments")
 	}
-	return nil
+	items, err := col.AllItems(c)
+	if err != nil {
+		return nil, err
+	}
+	attachments := make([]*Attachment, 0, len(items))
+	for _, item := range items {
+		if att, ok := item.(*Attachment); ok {
+			attachments = append(attachments, att)
+		}
+	}
+	return DownloadAttachments(c, attachments, dir, opts)
+}
+
+func (res *WorkPackage) AddAttachment(c *HalClient, fileName, contentType, description string, r io.Reader) (*Attachment, error) {
+	return c.AddAttachment(res, fileName, contentType, description, r)
 }
 
 func (res *WorkPackage) GetAuthor(c *HalClient) *User {
@@ -70,6 +92,64 @@ func (res *WorkPackage) GetAssignee(c *HalClient) *User {
 	return nil
 }
 
+func (res *WorkPackage) GetVersion(c *HalClient) *Version {
+	// Get embedded version or load from a link
+	val := res.GetEmbeddedResource("version", c)
+	if v, ok := val.(*Version); ok {
+		return v
+	}
+	return nil
+}
+
+// Assign the work package to a version.  Call `Update` to save the change.
+// A nil `v` removes the work package from its version.
+func (res *WorkPackage) SetVersion(v *Version) {
+	if v == nil {
+		res.AddLink("version", Link{})
+	} else if l := v.GetLink("self"); l != nil {
+		res.AddLink("version", *l)
+	}
+}
+
+func (res *WorkPackage) GetCategory(c *HalClient) *Category {
+	// Get embedded category or load from a link
+	val := res.GetEmbeddedResource("category", c)
+	if cat, ok := val.(*Category); ok {
+		return cat
+	}
+	return nil
+}
+
+// Set the work package's category.  Call `Update` to save the change.
+// A nil `cat` clears the category.
+func (res *WorkPackage) SetCategory(cat *Category) {
+	if cat == nil {
+		res.AddLink("category", Link{})
+	} else if l := cat.GetLink("self"); l != nil {
+		res.AddLink("category", *l)
+	}
+}
+
+func (res *WorkPackage) GetStatus(c *HalClient) *Status {
+	// Get embedded status or load from a link
+	val := res.GetEmbeddedResource("status", c)
+	if st, ok := val.(*Status); ok {
+		return st
+	}
+	return nil
+}
+
+// Set the work package's status.  Call `Update` to save the change.
+func (res *WorkPackage) SetStatus(st *Status) {
+	if l := st.GetLink("self"); l != nil {
+		res.AddLink("status", *l)
+	}
+}
+
+func (res *WorkPackage) LockVersion() int {
+	return res.GetInt("lockVersion")
+}
+
 func (res *WorkPackage) AddTimeEntry(c *HalClient, te *TimeEntry) (Resource, error) {
 	if l := res.GetLink("project"); l != nil {
 		te.AddLink("project", *l)
@@ -82,7 +162,7 @@ func (res *WorkPackage) AddTimeEntry(c *HalClient, te *TimeEntry) (Resource, err
 		te.SetSpentOn(time.Now())
 	}
 
-	return c.Post("/api/v3/time_entries", te)
+	return c.Post(c.endpoint("/time_entries", "timeEntries", "time_entries"), te)
 }
 
 // Register Factories